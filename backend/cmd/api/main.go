@@ -12,10 +12,16 @@ import (
 	"github.com/meet-clone/backend/internal/adapters/input/http/handlers"
 	"github.com/meet-clone/backend/internal/adapters/input/http/middleware"
 	"github.com/meet-clone/backend/internal/adapters/input/websocket"
+	"github.com/meet-clone/backend/internal/adapters/output/filesystem"
 	"github.com/meet-clone/backend/internal/adapters/output/mongodb"
 	"github.com/meet-clone/backend/internal/config"
+	"github.com/meet-clone/backend/internal/core/domain/attachment"
+	"github.com/meet-clone/backend/internal/core/domain/channel"
 	"github.com/meet-clone/backend/internal/core/domain/chat"
+	"github.com/meet-clone/backend/internal/core/domain/command"
+	"github.com/meet-clone/backend/internal/core/domain/notification"
 	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/core/domain/template"
 	"github.com/meet-clone/backend/internal/core/domain/user"
 	"github.com/meet-clone/backend/internal/pkg/cloudflare"
 	"github.com/meet-clone/backend/internal/pkg/jwt"
@@ -50,11 +56,55 @@ func main() {
 	userRepo := mongodb.NewUserRepository(mongoClient)
 	roomRepo := mongodb.NewRoomRepository(mongoClient)
 	chatRepo := mongodb.NewChatRepository(mongoClient)
+	readMarkerRepo := mongodb.NewReadMarkerRepository(mongoClient)
+	templateRepo := mongodb.NewTemplateRepository(mongoClient)
+	attachmentRepo := mongodb.NewAttachmentRepository(mongoClient)
+	notificationRepo := mongodb.NewNotificationRepository(mongoClient)
+	channelRepo := mongodb.NewChannelRepository(mongoClient)
+
+	// Initialize attachment storage
+	attachmentStorage, err := filesystem.NewStorage(cfg.AttachmentDir)
+	if err != nil {
+		logger.Error.Fatalf("Failed to prepare attachment storage: %v", err)
+	}
 
 	// Initialize services
-	userService := user.NewService(userRepo)
-	roomService := room.NewService(roomRepo)
-	chatService := chat.NewService(chatRepo)
+	userService := user.NewService(userRepo, cfg.OrganizationDomains)
+	roomService := room.NewService(roomRepo, room.Policy{
+		MaxCapacity:     room.DefaultPolicy().MaxCapacity,
+		MaxDuration:     cfg.MaxMeetingDuration,
+		MaxExtensions:   cfg.MaxExtensions,
+		MaxExtension:    cfg.MaxExtension,
+		MaxSpotlight:    cfg.SpotlightLimit,
+		LegalHoldAdmins: cfg.LegalHoldAdmins,
+	})
+	templateService := template.NewService(templateRepo, userService)
+	channelService := channel.NewService(channelRepo, userService, roomService, templateService)
+	// Chat, attachments and commands also serve channels, which look like rooms to them
+	conversations := channel.NewConversations(roomService, channelRepo)
+	chatRetention := chat.RetentionPolicy{
+		Default:       cfg.ChatRetention,
+		Organizations: cfg.ChatOrgRetention,
+	}
+	attachmentService := attachment.NewService(attachmentRepo, attachmentStorage, conversations, chat.NewRoomRetention(chatRetention, userService), attachment.Policy{
+		MaxSize:      cfg.AttachmentMaxSize,
+		AllowedTypes: cfg.AttachmentTypes,
+		Retention:    cfg.AttachmentRetention,
+	})
+	notificationService := notification.NewService(notificationRepo)
+	chatService := chat.NewService(chatRepo, readMarkerRepo, conversations, attachmentService, chat.Policy{MaxPins: cfg.ChatMaxPins},
+		chat.ChatMute(),
+		chat.MaxLength(cfg.ChatMaxLength),
+		chat.WordFilter(cfg.ChatBlockedWords, chat.WordFilterMode(cfg.ChatBlockedMode)),
+		chat.SlowMode(),
+	)
+
+	// Teams add their own chat commands with commandRegistry.Register
+	commandRegistry := command.NewRegistry()
+	if err := command.RegisterBuiltins(commandRegistry, roomService); err != nil {
+		logger.Error.Fatalf("Failed to register chat commands: %v", err)
+	}
+	commandService := command.NewService(commandRegistry, conversations, chatService)
 
 	// Initialize JWT service
 	jwtService := jwt.NewJWTService(cfg.JWTSecret, cfg.JWTExpiry)
@@ -63,16 +113,46 @@ func main() {
 	callsService := cloudflare.NewCallsService(cfg.CloudflareAppID, cfg.CloudflareAppSecret)
 
 	// Initialize WebSocket hub
-	wsHub := websocket.NewHub(chatService)
+	wsHub := websocket.NewHub(chatService, roomService, notificationService, commandService)
 	go wsHub.Run()
 	logger.Info.Println("WebSocket hub started")
 
+	// Start background room jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	reaper := room.NewReaper(roomRepo, roomService, wsHub, wsHub, callsService, room.ReaperConfig{
+		Interval:    cfg.ReaperInterval,
+		GracePeriod: cfg.ParticipantGrace,
+		IdleTimeout: cfg.RoomIdleTimeout,
+	})
+	go reaper.Run(jobsCtx)
+	logger.Info.Println("Room reaper started")
+
+	timekeeper := room.NewTimekeeper(roomRepo, roomService, wsHub, wsHub, callsService, room.TimekeeperConfig{
+		Interval: cfg.TimekeeperInterval,
+		Warnings: cfg.MeetingWarnings,
+	})
+	go timekeeper.Run(jobsCtx)
+	logger.Info.Println("Meeting timekeeper started")
+
+	janitor := attachment.NewJanitor(attachmentService, cfg.AttachmentCleanup)
+	go janitor.Run(jobsCtx)
+	logger.Info.Println("Attachment janitor started")
+
+	purger := chat.NewPurger(chatRepo, readMarkerRepo, attachmentService, roomService, userService, chatRetention, cfg.ChatPurgeInterval)
+	go purger.Run(jobsCtx)
+	logger.Info.Println("Chat purger started")
+
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(userService, jwtService)
-	roomHandler := handlers.NewRoomHandler(roomService)
-	chatHandler := handlers.NewChatHandler(chatService)
+	roomHandler := handlers.NewRoomHandler(roomService, templateService, wsHub)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	chatHandler := handlers.NewChatHandler(chatService, wsHub)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
 	callsHandler := handlers.NewCallsHandler(callsService, roomService)
-	wsHandler := websocket.NewHandler(wsHub, jwtService)
+	channelHandler := handlers.NewChannelHandler(channelService, chatHandler, wsHub)
+	wsHandler := websocket.NewHandler(wsHub, jwtService, channelService)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
@@ -81,7 +161,11 @@ func main() {
 	router := httpRouter.NewRouter(
 		authHandler,
 		roomHandler,
+		templateHandler,
 		chatHandler,
+		attachmentHandler,
+		notificationHandler,
+		channelHandler,
 		callsHandler,
 		wsHandler,
 		authMiddleware,
@@ -111,6 +195,7 @@ func main() {
 	<-quit
 
 	logger.Info.Println("Shutting down server...")
+	stopJobs()
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/meet-clone/backend/internal/adapters/input/http/middleware"
+	"github.com/meet-clone/backend/internal/core/domain/attachment"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+	"github.com/meet-clone/backend/internal/pkg/logger"
+)
+
+type AttachmentHandler struct {
+	attachmentService attachment.Service
+}
+
+func NewAttachmentHandler(attachmentService attachment.Service) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// Upload stores the "file" part of a multipart request. The part is
+// streamed to storage rather than buffered in memory.
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		respondError(w, errors.NewValidationError("request must be multipart/form-data"), http.StatusBadRequest)
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			respondError(w, errors.NewValidationError("file is required"), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			respondError(w, errors.NewValidationError("invalid multipart body"), http.StatusBadRequest)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		a, err := h.attachmentService.Upload(r.Context(), roomID, claims.UserID, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				respondError(w, appErr, getStatusCode(appErr.Type))
+				return
+			}
+			respondError(w, errors.NewInternalError("failed to upload attachment", err), http.StatusInternalServerError)
+			return
+		}
+
+		respondJSON(w, a, http.StatusCreated)
+		return
+	}
+}
+
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+	attachmentID := vars["attachmentId"]
+
+	a, content, err := h.attachmentService.Open(r.Context(), roomID, attachmentID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to download attachment", err), http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+
+	// Always download rather than render, so uploaded HTML or SVG cannot
+	// run in the application's origin
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		logger.Error.Printf("Failed to send attachment %s: %v", a.ID, err)
+	}
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/meet-clone/backend/internal/adapters/input/http/middleware"
+	"github.com/meet-clone/backend/internal/core/domain/channel"
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+// ChannelEvents delivers channel events and drops the connections of
+// removed members.
+type ChannelEvents interface {
+	room.EventPublisher
+	Disconnect(roomID, userID string)
+}
+
+type ChannelHandler struct {
+	channelService channel.Service
+	chatHandler    *ChatHandler
+	events         ChannelEvents
+}
+
+func NewChannelHandler(channelService channel.Service, chatHandler *ChatHandler, events ChannelEvents) *ChannelHandler {
+	return &ChannelHandler{
+		channelService: channelService,
+		chatHandler:    chatHandler,
+		events:         events,
+	}
+}
+
+type CreateChannelRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Shared      bool   `json:"shared"`
+	TemplateID  string `json:"template_id"`
+}
+
+func (h *ChannelHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	ch, err := h.channelService.CreateChannel(r.Context(), claims.UserID, channel.CreateOptions{
+		Name:        req.Name,
+		Description: req.Description,
+		Shared:      req.Shared,
+		TemplateID:  req.TemplateID,
+	})
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to create channel", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, ch, http.StatusCreated)
+}
+
+func (h *ChannelHandler) ListChannels(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	channels, err := h.channelService.ListChannels(r.Context(), claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to list channels", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, channels, http.StatusOK)
+}
+
+func (h *ChannelHandler) GetChannel(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	channelID := vars["id"]
+
+	ch, err := h.channelService.GetChannel(r.Context(), channelID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to get channel", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, ch, http.StatusOK)
+}
+
+func (h *ChannelHandler) JoinChannel(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	channelID := vars["id"]
+
+	ch, err := h.channelService.JoinChannel(r.Context(), channelID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to join channel", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.events.Publish(ch.ID, "channel_updated", ch)
+
+	respondJSON(w, ch, http.StatusOK)
+}
+
+type AddMemberRequest struct {
+	UserID string `json:"user_id"`
+}
+
+func (h *ChannelHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	channelID := vars["id"]
+
+	var req AddMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	ch, err := h.channelService.AddMember(r.Context(), channelID, claims.UserID, req.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to add member", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.events.Publish(ch.ID, "channel_updated", ch)
+
+	respondJSON(w, ch, http.StatusOK)
+}
+
+func (h *ChannelHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	channelID := vars["id"]
+	targetID := vars["userId"]
+
+	ch, err := h.channelService.RemoveMember(r.Context(), channelID, claims.UserID, targetID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to remove member", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Removed members stop receiving the channel's messages at once
+	h.events.Disconnect(ch.ID, targetID)
+	h.events.Publish(ch.ID, "channel_updated", ch)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StartCallRequest starts a call of the channel. Fields override the
+// channel's template.
+type StartCallRequest struct {
+	Title               string   `json:"title"`
+	MaxCapacity         int      `json:"max_capacity"`
+	MaxDurationMinutes  int      `json:"max_duration_minutes"`
+	ChatDisabled        *bool    `json:"chat_disabled"`
+	PrivateChatDisabled *bool    `json:"private_chat_disabled"`
+	AllowedReactions    []string `json:"allowed_reactions"`
+	ChatRetentionDays   *int     `json:"chat_retention_days"`
+}
+
+func (h *ChannelHandler) StartCall(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	channelID := vars["id"]
+
+	// The body is optional
+	var req StartCallRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	rm, err := h.channelService.StartCall(r.Context(), channelID, claims.UserID, channel.CallOptions{
+		Title:       req.Title,
+		MaxCapacity: req.MaxCapacity,
+		MaxDuration: time.Duration(req.MaxDurationMinutes) * time.Minute,
+		Settings: room.SettingsUpdate{
+			ChatDisabled:        req.ChatDisabled,
+			PrivateChatDisabled: req.PrivateChatDisabled,
+			AllowedReactions:    req.AllowedReactions,
+			ChatRetentionDays:   req.ChatRetentionDays,
+		},
+	})
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to start call", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.events.Publish(channelID, "call_started", rm)
+
+	respondJSON(w, rm, http.StatusCreated)
+}
+
+func (h *ChannelHandler) ListCalls(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	channelID := vars["id"]
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	filter := room.ListFilter{
+		Status: room.RoomStatus(query.Get("status")),
+		Limit:  limit,
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		var err error
+		if filter.After, err = room.DecodeCursor(cursor); err != nil {
+			respondError(w, errors.NewValidationError("invalid cursor"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.channelService.ListCalls(r.Context(), channelID, claims.UserID, filter)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to list calls", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, page, http.StatusOK)
+}
+
+// MembersOnly lets a chat request through only for members of the channel
+// in the id path variable, whose messages are stored under the channel ID.
+func (h *ChannelHandler) MembersOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+			return
+		}
+
+		vars := mux.Vars(r)
+		if _, err := h.channelService.GetChannel(r.Context(), vars["id"], claims.UserID); err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				respondError(w, appErr, getStatusCode(appErr.Type))
+				return
+			}
+			respondError(w, errors.NewInternalError("failed to get channel", err), http.StatusInternalServerError)
+			return
+		}
+
+		next(w, r)
+	}
+}
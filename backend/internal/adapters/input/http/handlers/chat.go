@@ -1,36 +1,81 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/meet-clone/backend/internal/adapters/input/http/middleware"
 	"github.com/meet-clone/backend/internal/core/domain/chat"
 	"github.com/meet-clone/backend/internal/pkg/errors"
+	"github.com/meet-clone/backend/internal/pkg/logger"
 )
 
+// ChatEvents delivers chat events to the connected clients of a room.
+type ChatEvents interface {
+	// PublishReadMarker delivers a read marker to those who may see it.
+	PublishReadMarker(marker *chat.ReadMarker)
+}
+
 type ChatHandler struct {
 	chatService chat.Service
+	events      ChatEvents
 }
 
-func NewChatHandler(chatService chat.Service) *ChatHandler {
+func NewChatHandler(chatService chat.Service, events ChatEvents) *ChatHandler {
 	return &ChatHandler{
 		chatService: chatService,
+		events:      events,
 	}
 }
 
+type MarkReadRequest struct {
+	MessageID string `json:"message_id"`
+}
+
 func (h *ChatHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
 	vars := mux.Vars(r)
 	roomID := vars["id"]
 
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	messageQuery := chat.MessageQuery{
+		RoomID:   roomID,
+		ViewerID: claims.UserID,
+		Limit:    limit,
+	}
+	if query.Has("offset") {
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		messageQuery.Offset = &offset
+	}
 
-	if limit == 0 {
-		limit = 50
+	var err error
+	if cursor := query.Get("before"); cursor != "" {
+		if messageQuery.Before, err = chat.DecodeCursor(cursor); err != nil {
+			respondError(w, errors.NewValidationError("invalid before cursor"), http.StatusBadRequest)
+			return
+		}
 	}
+	if cursor := query.Get("after"); cursor != "" {
+		if messageQuery.After, err = chat.DecodeCursor(cursor); err != nil {
+			respondError(w, errors.NewValidationError("invalid after cursor"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Requests with an offset get the old response, a bare list of messages
+	legacy := messageQuery.Offset != nil
 
-	messages, err := h.chatService.GetMessages(r.Context(), roomID, limit, offset)
+	page, err := h.chatService.GetMessages(r.Context(), messageQuery)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			respondError(w, appErr, getStatusCode(appErr.Type))
@@ -40,5 +85,215 @@ func (h *ChatHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, messages, http.StatusOK)
+	if legacy {
+		w.Header().Set("Deprecation", "true")
+		respondJSON(w, page.Messages, http.StatusOK)
+		return
+	}
+
+	respondJSON(w, page, http.StatusOK)
+}
+
+func (h *ChatHandler) GetThread(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+	messageID := vars["messageId"]
+
+	thread, err := h.chatService.GetThread(r.Context(), roomID, messageID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to get thread", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, thread, http.StatusOK)
+}
+
+func (h *ChatHandler) SearchMessages(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	search := chat.SearchQuery{
+		Text:     query.Get("q"),
+		ViewerID: claims.UserID,
+		RoomID:   query.Get("room_id"),
+		Limit:    limit,
+	}
+
+	var err error
+	if search.From, err = parseTimeParam(query.Get("from")); err != nil {
+		respondError(w, errors.NewValidationError("from must be an RFC 3339 timestamp"), http.StatusBadRequest)
+		return
+	}
+	if search.To, err = parseTimeParam(query.Get("to")); err != nil {
+		respondError(w, errors.NewValidationError("to must be an RFC 3339 timestamp"), http.StatusBadRequest)
+		return
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		if search.After, err = chat.DecodeCursor(cursor); err != nil {
+			respondError(w, errors.NewValidationError("invalid cursor"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.chatService.SearchMessages(r.Context(), search)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to search messages", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, page, http.StatusOK)
+}
+
+// ExportMessages streams the whole chat of a room as json, txt, html or md,
+// with times in the tz time zone.
+func (h *ChatHandler) ExportMessages(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	loc := time.UTC
+	if tz := query.Get("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			respondError(w, errors.NewValidationError("tz must be an IANA time zone such as Europe/Berlin"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	transcript, ok := newTranscript(format, w, loc)
+	if !ok {
+		respondError(w, errors.NewValidationError("format must be json, txt, html or md"), http.StatusBadRequest)
+		return
+	}
+
+	// Long chats take longer to stream than the server write timeout allows
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	err := h.chatService.ExportMessages(r.Context(), roomID, claims.UserID, transcript)
+	if err == nil {
+		return
+	}
+
+	// Once streaming started the status is sent, so the export is cut short
+	if transcript.Started() {
+		logger.Error.Printf("Failed to export chat of room %s: %v", roomID, err)
+		return
+	}
+	if appErr, ok := err.(*errors.AppError); ok {
+		respondError(w, appErr, getStatusCode(appErr.Type))
+		return
+	}
+	respondError(w, errors.NewInternalError("failed to export messages", err), http.StatusInternalServerError)
+}
+
+// GetPinnedMessages returns the pinned messages of a room.
+func (h *ChatHandler) GetPinnedMessages(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	pinned, err := h.chatService.GetPinnedMessages(r.Context(), roomID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to get pinned messages", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, pinned, http.StatusOK)
+}
+
+// GetUnread returns how many messages the caller has not read in a room.
+func (h *ChatHandler) GetUnread(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	unread, err := h.chatService.GetUnread(r.Context(), roomID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to count unread messages", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, unread, http.StatusOK)
+}
+
+// MarkRead moves the caller's read marker forward to a message.
+func (h *ChatHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	var req MarkReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	marker, err := h.chatService.MarkRead(r.Context(), roomID, claims.UserID, req.MessageID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to mark messages as read", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.events.PublishReadMarker(marker)
+
+	respondJSON(w, marker, http.StatusOK)
 }
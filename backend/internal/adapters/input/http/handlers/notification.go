@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/meet-clone/backend/internal/adapters/input/http/middleware"
+	"github.com/meet-clone/backend/internal/core/domain/notification"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+type NotificationHandler struct {
+	notificationService notification.Service
+}
+
+func NewNotificationHandler(notificationService notification.Service) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	unreadOnly, _ := strconv.ParseBool(query.Get("unread"))
+
+	filter := notification.ListFilter{
+		UserID:     claims.UserID,
+		UnreadOnly: unreadOnly,
+		Limit:      limit,
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		var err error
+		if filter.After, err = notification.DecodeCursor(cursor); err != nil {
+			respondError(w, errors.NewValidationError("invalid cursor"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.notificationService.List(r.Context(), filter)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to list notifications", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, page, http.StatusOK)
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	notificationID := vars["id"]
+
+	if err := h.notificationService.MarkRead(r.Context(), claims.UserID, notificationID); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to mark notification as read", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.notificationService.MarkAllRead(r.Context(), claims.UserID); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to mark notifications as read", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
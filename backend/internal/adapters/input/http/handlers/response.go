@@ -37,7 +37,7 @@ func getStatusCode(errorType errors.ErrorType) int {
 		return http.StatusUnauthorized
 	case errors.ErrorTypeForbidden:
 		return http.StatusForbidden
-	case errors.ErrorTypeAlreadyExists:
+	case errors.ErrorTypeAlreadyExists, errors.ErrorTypeConflict:
 		return http.StatusConflict
 	default:
 		return http.StatusInternalServerError
@@ -1,25 +1,56 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/meet-clone/backend/internal/adapters/input/http/middleware"
 	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/core/domain/template"
 	"github.com/meet-clone/backend/internal/pkg/errors"
 )
 
+// RoomEvents delivers room events and forgets the presence of rooms that
+// ended.
+type RoomEvents interface {
+	room.EventPublisher
+	Forget(roomID string)
+}
+
 type RoomHandler struct {
-	roomService room.Service
+	roomService     room.Service
+	templateService template.Service
+	events          RoomEvents
 }
 
-func NewRoomHandler(roomService room.Service) *RoomHandler {
+func NewRoomHandler(roomService room.Service, templateService template.Service, events RoomEvents) *RoomHandler {
 	return &RoomHandler{
-		roomService: roomService,
+		roomService:     roomService,
+		templateService: templateService,
+		events:          events,
 	}
 }
 
+// CreateRoomRequest creates a room, optionally from a template. Fields set
+// in the request override the template.
+type CreateRoomRequest struct {
+	TemplateID          string   `json:"template_id"`
+	Title               string   `json:"title"`
+	MaxCapacity         int      `json:"max_capacity"`
+	MaxDurationMinutes  int      `json:"max_duration_minutes"`
+	LobbyEnabled        *bool    `json:"lobby_enabled"`
+	ChatDisabled        *bool    `json:"chat_disabled"`
+	PrivateChatDisabled *bool    `json:"private_chat_disabled"`
+	AllowedReactions    []string `json:"allowed_reactions"`
+	ChatRetentionDays   *int     `json:"chat_retention_days"`
+}
+
 func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.GetUserFromContext(r.Context())
 	if !ok {
@@ -27,7 +58,53 @@ func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rm, err := h.roomService.CreateRoom(r.Context(), claims.UserID)
+	// The body is optional
+	var req CreateRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	var opts room.CreateOptions
+	if req.TemplateID != "" {
+		var err error
+		opts, err = h.templateService.RoomOptions(r.Context(), req.TemplateID, claims.UserID)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				respondError(w, appErr, getStatusCode(appErr.Type))
+				return
+			}
+			respondError(w, errors.NewInternalError("failed to load template", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if req.Title != "" {
+		opts.Title = req.Title
+	}
+	if req.MaxCapacity != 0 {
+		opts.MaxCapacity = req.MaxCapacity
+	}
+	if req.MaxDurationMinutes != 0 {
+		opts.MaxDuration = time.Duration(req.MaxDurationMinutes) * time.Minute
+	}
+	if req.LobbyEnabled != nil {
+		opts.Settings.LobbyEnabled = *req.LobbyEnabled
+	}
+	if req.ChatDisabled != nil {
+		opts.Settings.ChatDisabled = *req.ChatDisabled
+	}
+	if req.PrivateChatDisabled != nil {
+		opts.Settings.PrivateChatDisabled = *req.PrivateChatDisabled
+	}
+	if req.AllowedReactions != nil {
+		opts.Settings.AllowedReactions = req.AllowedReactions
+	}
+	if req.ChatRetentionDays != nil {
+		opts.Settings.ChatRetentionDays = *req.ChatRetentionDays
+	}
+
+	rm, err := h.roomService.CreateRoom(r.Context(), claims.UserID, opts)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			respondError(w, appErr, getStatusCode(appErr.Type))
@@ -57,6 +134,25 @@ func (h *RoomHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, rm, http.StatusOK)
 }
 
+// RoomsOnly rejects requests whose id path variable is not a meeting room,
+// so channels are only reachable through the channel routes, which check
+// membership.
+func (h *RoomHandler) RoomsOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if _, err := h.roomService.GetRoomDetails(r.Context(), vars["id"]); err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				respondError(w, appErr, getStatusCode(appErr.Type))
+				return
+			}
+			respondError(w, errors.NewInternalError("failed to get room", err), http.StatusInternalServerError)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 type JoinRoomRequest struct {
 	UserName string `json:"user_name"`
 	Avatar   string `json:"avatar"`
@@ -88,6 +184,15 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The caller is waiting in the lobby until a host admits them
+	if rm.MustWait(claims.UserID) {
+		h.events.Publish(rm.ID, "lobby_updated", map[string]interface{}{
+			"lobby": rm.Lobby,
+		})
+		respondJSON(w, rm, http.StatusAccepted)
+		return
+	}
+
 	respondJSON(w, rm, http.StatusOK)
 }
 
@@ -110,6 +215,9 @@ func (h *RoomHandler) LeaveRoom(w http.ResponseWriter, r *http.Request) {
 		respondError(w, errors.NewInternalError("failed to leave room", err), http.StatusInternalServerError)
 		return
 	}
+	if !rm.IsActive() {
+		h.events.Forget(rm.ID)
+	}
 
 	respondJSON(w, rm, http.StatusOK)
 }
@@ -133,6 +241,7 @@ func (h *RoomHandler) EndRoom(w http.ResponseWriter, r *http.Request) {
 		respondError(w, errors.NewInternalError("failed to end room", err), http.StatusInternalServerError)
 		return
 	}
+	h.events.Forget(roomID)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -154,6 +263,63 @@ func (h *RoomHandler) GetParticipants(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, participants, http.StatusOK)
 }
 
+func (h *RoomHandler) ListRooms(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	filter := room.ListFilter{
+		ViewerID:      claims.UserID,
+		Status:        room.RoomStatus(query.Get("status")),
+		CreatedBy:     query.Get("creator"),
+		ParticipantID: query.Get("participant"),
+		Query:         query.Get("q"),
+		Sort:          room.SortOrder(query.Get("sort")),
+		Limit:         limit,
+	}
+
+	var err error
+	if filter.CreatedAfter, err = parseTimeParam(query.Get("created_after")); err != nil {
+		respondError(w, errors.NewValidationError("created_after must be an RFC 3339 timestamp"), http.StatusBadRequest)
+		return
+	}
+	if filter.CreatedBefore, err = parseTimeParam(query.Get("created_before")); err != nil {
+		respondError(w, errors.NewValidationError("created_before must be an RFC 3339 timestamp"), http.StatusBadRequest)
+		return
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		if filter.After, err = room.DecodeCursor(cursor); err != nil {
+			respondError(w, errors.NewValidationError(err.Error()), http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.roomService.ListRooms(r.Context(), filter)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to list rooms", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, page, http.StatusOK)
+}
+
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
 func (h *RoomHandler) GetUserRooms(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.GetUserFromContext(r.Context())
 	if !ok {
@@ -173,3 +339,186 @@ func (h *RoomHandler) GetUserRooms(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, rooms, http.StatusOK)
 }
+
+type ExtendRoomRequest struct {
+	Minutes int `json:"minutes"`
+}
+
+func (h *RoomHandler) ExtendRoom(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	var req ExtendRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	rm, err := h.roomService.ExtendRoom(r.Context(), roomID, claims.UserID, time.Duration(req.Minutes)*time.Minute)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to extend room", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.events.Publish(rm.ID, "meeting_extended", map[string]interface{}{
+		"expires_at": rm.ExpiresAt,
+		"extensions": rm.Extensions,
+	})
+
+	respondJSON(w, rm, http.StatusOK)
+}
+
+// UpdateSettingsRequest changes the settings of a room. Omitted fields are
+// left as they are.
+type UpdateSettingsRequest struct {
+	LobbyEnabled        *bool    `json:"lobby_enabled"`
+	ChatDisabled        *bool    `json:"chat_disabled"`
+	PrivateChatDisabled *bool    `json:"private_chat_disabled"`
+	SlowModeSeconds     *int     `json:"slow_mode_seconds"`
+	AllowedReactions    []string `json:"allowed_reactions"`
+	ChatRetentionDays   *int     `json:"chat_retention_days"`
+}
+
+func (h *RoomHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	var req UpdateSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	rm, err := h.roomService.UpdateSettings(r.Context(), roomID, claims.UserID, room.SettingsUpdate{
+		LobbyEnabled:        req.LobbyEnabled,
+		ChatDisabled:        req.ChatDisabled,
+		PrivateChatDisabled: req.PrivateChatDisabled,
+		SlowModeSeconds:     req.SlowModeSeconds,
+		AllowedReactions:    req.AllowedReactions,
+		ChatRetentionDays:   req.ChatRetentionDays,
+	})
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to update room settings", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.events.Publish(rm.ID, "settings_updated", rm.Settings)
+
+	respondJSON(w, rm, http.StatusOK)
+}
+
+type LegalHoldRequest struct {
+	Held bool `json:"held"`
+}
+
+// SetLegalHold places a room on legal hold, which keeps its chat past the
+// retention period, or releases it.
+func (h *RoomHandler) SetLegalHold(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	var req LegalHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	rm, err := h.roomService.SetLegalHold(r.Context(), roomID, claims.UserID, req.Held)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to change legal hold", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, rm, http.StatusOK)
+}
+
+func (h *RoomHandler) GetAttendance(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	roomID := vars["id"]
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		respondError(w, errors.NewValidationError("format must be json or csv"), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.roomService.GetAttendance(r.Context(), roomID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to get attendance", err), http.StatusInternalServerError)
+		return
+	}
+
+	if format == "json" {
+		respondJSON(w, report, http.StatusOK)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"attendance-%s.csv\"", report.RoomID))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"user_id", "name", "joins", "total_seconds", "first_joined_at", "last_left_at", "present"})
+	for _, a := range report.Attendees {
+		cw.Write([]string{
+			a.UserID,
+			a.Name,
+			strconv.Itoa(a.Joins),
+			strconv.FormatInt(a.TotalSeconds, 10),
+			formatCSVTime(a.FirstJoinedAt),
+			formatCSVTime(a.LastLeftAt),
+			strconv.FormatBool(a.Present),
+		})
+	}
+	cw.Flush()
+}
+
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
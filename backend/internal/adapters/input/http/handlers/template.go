@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/meet-clone/backend/internal/adapters/input/http/middleware"
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/core/domain/template"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+type TemplateHandler struct {
+	templateService template.Service
+}
+
+func NewTemplateHandler(templateService template.Service) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+type TemplateRequest struct {
+	Name               string        `json:"name"`
+	Description        string        `json:"description"`
+	Title              string        `json:"title"`
+	MaxCapacity        int           `json:"max_capacity"`
+	MaxDurationMinutes int           `json:"max_duration_minutes"`
+	Settings           room.Settings `json:"settings"`
+	Shared             bool          `json:"shared"`
+}
+
+func (req TemplateRequest) input() template.Input {
+	return template.Input{
+		Name:               req.Name,
+		Description:        req.Description,
+		Title:              req.Title,
+		MaxCapacity:        req.MaxCapacity,
+		MaxDurationMinutes: req.MaxDurationMinutes,
+		Settings:           req.Settings,
+		Shared:             req.Shared,
+	}
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	var req TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.templateService.CreateTemplate(r.Context(), claims.UserID, req.input())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to create template", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, t, http.StatusCreated)
+}
+
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	templates, err := h.templateService.ListTemplates(r.Context(), claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to list templates", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, templates, http.StatusOK)
+}
+
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	templateID := vars["id"]
+
+	t, err := h.templateService.GetTemplate(r.Context(), templateID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to get template", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, t, http.StatusOK)
+}
+
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	templateID := vars["id"]
+
+	var req TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, errors.NewValidationError("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.templateService.UpdateTemplate(r.Context(), templateID, claims.UserID, req.input())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to update template", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, t, http.StatusOK)
+}
+
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		respondError(w, errors.NewUnauthorizedError("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	templateID := vars["id"]
+
+	err := h.templateService.DeleteTemplate(r.Context(), templateID, claims.UserID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			respondError(w, appErr, getStatusCode(appErr.Type))
+			return
+		}
+		respondError(w, errors.NewInternalError("failed to delete template", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
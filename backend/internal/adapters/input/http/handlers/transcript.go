@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/meet-clone/backend/internal/core/domain/chat"
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+// transcriptFlushEvery is how many messages are written between flushes,
+// so long exports reach the client while they are produced.
+const transcriptFlushEvery = 100
+
+const transcriptTimeLayout = "2006-01-02 15:04:05 MST"
+
+// newTranscript returns the chat.Transcript writing the given format to w,
+// or false if the format is unknown.
+func newTranscript(format string, w http.ResponseWriter, loc *time.Location) (transcript, bool) {
+	base := transcriptBase{w: w, loc: loc}
+	switch format {
+	case "json":
+		return &jsonTranscript{transcriptBase: base}, true
+	case "txt":
+		return &textTranscript{transcriptBase: base}, true
+	case "html":
+		return &htmlTranscript{transcriptBase: base}, true
+	case "md":
+		return &markdownTranscript{transcriptBase: base}, true
+	}
+	return nil, false
+}
+
+type transcript interface {
+	chat.Transcript
+	// Started reports whether the response was started, after which errors
+	// can no longer be reported to the client.
+	Started() bool
+}
+
+type transcriptBase struct {
+	w       http.ResponseWriter
+	loc     *time.Location
+	started bool
+	written int
+}
+
+func (t *transcriptBase) Started() bool {
+	return t.started
+}
+
+func (t *transcriptBase) start(rm *room.Room, contentType, extension string) {
+	t.w.Header().Set("Content-Type", contentType)
+	t.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"chat-%s.%s\"", rm.ID, extension))
+	t.w.WriteHeader(http.StatusOK)
+	t.started = true
+}
+
+// wrote counts a written message and flushes the response periodically.
+func (t *transcriptBase) wrote() {
+	t.written++
+	if t.written%transcriptFlushEvery != 0 {
+		return
+	}
+	if f, ok := t.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (t *transcriptBase) time(ts time.Time) string {
+	return ts.In(t.loc).Format(transcriptTimeLayout)
+}
+
+func (t *transcriptBase) header(rm *room.Room) (title, exported string) {
+	title = rm.Title
+	if title == "" {
+		title = rm.ID
+	}
+	return title, t.time(time.Now())
+}
+
+// notes lists what a plain rendering of the message would otherwise lose.
+func transcriptNotes(msg *chat.Message) []string {
+	var notes []string
+	if msg.ParentID != "" {
+		notes = append(notes, "reply")
+	}
+	if msg.IsPrivate() {
+		notes = append(notes, "private")
+	}
+	if !msg.EditedAt.IsZero() && !msg.Deleted {
+		notes = append(notes, "edited")
+	}
+	return notes
+}
+
+// transcriptText is the text of a message as it appears in a transcript.
+func transcriptText(msg *chat.Message) string {
+	if msg.Deleted {
+		return "[message deleted]"
+	}
+	text := msg.Message
+	if msg.Attachment != nil {
+		if text != "" {
+			text += " "
+		}
+		text += "[attachment: " + msg.Attachment.FileName + "]"
+	}
+	return text
+}
+
+type jsonTranscript struct {
+	transcriptBase
+}
+
+func (t *jsonTranscript) Begin(rm *room.Room) error {
+	t.start(rm, "application/json", "json")
+
+	head, err := json.Marshal(map[string]interface{}{
+		"room_id":     rm.ID,
+		"title":       rm.Title,
+		"timezone":    t.loc.String(),
+		"exported_at": time.Now().In(t.loc),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Leave the object open and append the messages to it
+	_, err = io.WriteString(t.w, string(head[:len(head)-1])+`,"messages":[`)
+	return err
+}
+
+func (t *jsonTranscript) Write(msg *chat.Message) error {
+	msg.Timestamp = msg.Timestamp.In(t.loc)
+	if !msg.EditedAt.IsZero() {
+		msg.EditedAt = msg.EditedAt.In(t.loc)
+	}
+	if !msg.DeletedAt.IsZero() {
+		msg.DeletedAt = msg.DeletedAt.In(t.loc)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if t.written > 0 {
+		data = append([]byte{','}, data...)
+	}
+	if _, err := t.w.Write(data); err != nil {
+		return err
+	}
+
+	t.wrote()
+	return nil
+}
+
+func (t *jsonTranscript) End() error {
+	_, err := io.WriteString(t.w, "]}\n")
+	return err
+}
+
+type textTranscript struct {
+	transcriptBase
+}
+
+func (t *textTranscript) Begin(rm *room.Room) error {
+	t.start(rm, "text/plain; charset=utf-8", "txt")
+
+	title, exported := t.header(rm)
+	_, err := fmt.Fprintf(t.w, "Chat transcript: %s\nExported: %s\n\n", title, exported)
+	return err
+}
+
+func (t *textTranscript) Write(msg *chat.Message) error {
+	line := fmt.Sprintf("[%s] %s: ", t.time(msg.Timestamp), msg.UserName)
+	if notes := transcriptNotes(msg); len(notes) > 0 {
+		line = fmt.Sprintf("[%s] %s (%s): ", t.time(msg.Timestamp), msg.UserName, strings.Join(notes, ", "))
+	}
+
+	// Indent continuation lines so every message starts a new line
+	text := strings.ReplaceAll(transcriptText(msg), "\n", "\n    ")
+	if _, err := io.WriteString(t.w, line+text+"\n"); err != nil {
+		return err
+	}
+
+	t.wrote()
+	return nil
+}
+
+func (t *textTranscript) End() error {
+	return nil
+}
+
+type markdownTranscript struct {
+	transcriptBase
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+func (t *markdownTranscript) Begin(rm *room.Room) error {
+	t.start(rm, "text/markdown; charset=utf-8", "md")
+
+	title, exported := t.header(rm)
+	_, err := fmt.Fprintf(t.w, "# Chat transcript: %s\n\n_Exported %s_\n\n", markdownEscaper.Replace(title), exported)
+	return err
+}
+
+func (t *markdownTranscript) Write(msg *chat.Message) error {
+	heading := fmt.Sprintf("**%s** · %s", markdownEscaper.Replace(msg.UserName), t.time(msg.Timestamp))
+	if notes := transcriptNotes(msg); len(notes) > 0 {
+		heading += " _(" + strings.Join(notes, ", ") + ")_"
+	}
+
+	// Hard line breaks keep multi-line messages together
+	text := strings.ReplaceAll(markdownEscaper.Replace(transcriptText(msg)), "\n", "  \n")
+	if _, err := fmt.Fprintf(t.w, "%s  \n%s\n\n", heading, text); err != nil {
+		return err
+	}
+
+	t.wrote()
+	return nil
+}
+
+func (t *markdownTranscript) End() error {
+	return nil
+}
+
+type htmlTranscript struct {
+	transcriptBase
+}
+
+// transcriptStyle makes the HTML export readable without any other files.
+const transcriptStyle = `body{font-family:system-ui,sans-serif;max-width:48rem;margin:2rem auto;padding:0 1rem;color:#202124}
+h1{font-size:1.5rem}.meta{color:#5f6368}ol{list-style:none;padding:0}
+li{padding:.5rem 0;border-bottom:1px solid #e8eaed}.reply{margin-left:2rem}
+.author{font-weight:600}.time,.notes{color:#5f6368;font-size:.85rem;margin-left:.5rem}
+.text{white-space:pre-wrap;margin-top:.25rem}.deleted .text{color:#5f6368;font-style:italic}`
+
+func (t *htmlTranscript) Begin(rm *room.Room) error {
+	t.start(rm, "text/html; charset=utf-8", "html")
+
+	title, exported := t.header(rm)
+	title = html.EscapeString(title)
+	_, err := fmt.Fprintf(t.w,
+		"<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n<title>Chat transcript: %s</title>\n<style>%s</style>\n</head>\n<body>\n<h1>Chat transcript: %s</h1>\n<p class=\"meta\">Exported %s</p>\n<ol>\n",
+		title, transcriptStyle, title, html.EscapeString(exported),
+	)
+	return err
+}
+
+func (t *htmlTranscript) Write(msg *chat.Message) error {
+	classes := "message"
+	if msg.ParentID != "" {
+		classes += " reply"
+	}
+	if msg.Deleted {
+		classes += " deleted"
+	}
+
+	notes := ""
+	if n := transcriptNotes(msg); len(n) > 0 {
+		notes = fmt.Sprintf(`<span class="notes">%s</span>`, html.EscapeString(strings.Join(n, ", ")))
+	}
+
+	_, err := fmt.Fprintf(t.w,
+		"<li class=\"%s\"><span class=\"author\">%s</span><span class=\"time\">%s</span>%s<div class=\"text\">%s</div></li>\n",
+		classes,
+		html.EscapeString(msg.UserName),
+		html.EscapeString(t.time(msg.Timestamp)),
+		notes,
+		html.EscapeString(transcriptText(msg)),
+	)
+	if err != nil {
+		return err
+	}
+
+	t.wrote()
+	return nil
+}
+
+func (t *htmlTranscript) End() error {
+	_, err := io.WriteString(t.w, "</ol>\n</body>\n</html>\n")
+	return err
+}
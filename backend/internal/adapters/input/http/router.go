@@ -12,34 +12,46 @@ import (
 )
 
 type Router struct {
-	router         *mux.Router
-	authHandler    *httpHandlers.AuthHandler
-	roomHandler    *httpHandlers.RoomHandler
-	chatHandler    *httpHandlers.ChatHandler
-	callsHandler   *httpHandlers.CallsHandler
-	wsHandler      *websocket.Handler
-	authMiddleware *middleware.AuthMiddleware
-	config         *config.Config
+	router              *mux.Router
+	authHandler         *httpHandlers.AuthHandler
+	roomHandler         *httpHandlers.RoomHandler
+	templateHandler     *httpHandlers.TemplateHandler
+	chatHandler         *httpHandlers.ChatHandler
+	attachmentHandler   *httpHandlers.AttachmentHandler
+	notificationHandler *httpHandlers.NotificationHandler
+	channelHandler      *httpHandlers.ChannelHandler
+	callsHandler        *httpHandlers.CallsHandler
+	wsHandler           *websocket.Handler
+	authMiddleware      *middleware.AuthMiddleware
+	config              *config.Config
 }
 
 func NewRouter(
 	authHandler *httpHandlers.AuthHandler,
 	roomHandler *httpHandlers.RoomHandler,
+	templateHandler *httpHandlers.TemplateHandler,
 	chatHandler *httpHandlers.ChatHandler,
+	attachmentHandler *httpHandlers.AttachmentHandler,
+	notificationHandler *httpHandlers.NotificationHandler,
+	channelHandler *httpHandlers.ChannelHandler,
 	callsHandler *httpHandlers.CallsHandler,
 	wsHandler *websocket.Handler,
 	authMiddleware *middleware.AuthMiddleware,
 	cfg *config.Config,
 ) *Router {
 	return &Router{
-		router:         mux.NewRouter(),
-		authHandler:    authHandler,
-		roomHandler:    roomHandler,
-		chatHandler:    chatHandler,
-		callsHandler:   callsHandler,
-		wsHandler:      wsHandler,
-		authMiddleware: authMiddleware,
-		config:         cfg,
+		router:              mux.NewRouter(),
+		authHandler:         authHandler,
+		roomHandler:         roomHandler,
+		templateHandler:     templateHandler,
+		chatHandler:         chatHandler,
+		attachmentHandler:   attachmentHandler,
+		notificationHandler: notificationHandler,
+		channelHandler:      channelHandler,
+		callsHandler:        callsHandler,
+		wsHandler:           wsHandler,
+		authMiddleware:      authMiddleware,
+		config:              cfg,
 	}
 }
 
@@ -47,7 +59,7 @@ func (r *Router) Setup() http.Handler {
 	// Apply CORS
 	corsHandler := handlers.CORS(
 		handlers.AllowedOrigins([]string{r.config.CORSOrigin}),
-		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
 		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
 		handlers.AllowCredentials(),
 	)
@@ -72,20 +84,80 @@ func (r *Router) Setup() http.Handler {
 	rooms := api.PathPrefix("/rooms").Subrouter()
 	rooms.Use(r.authMiddleware.Authenticate)
 	rooms.HandleFunc("", r.roomHandler.CreateRoom).Methods("POST")
+	rooms.HandleFunc("", r.roomHandler.ListRooms).Methods("GET")
 	rooms.HandleFunc("/my-rooms", r.roomHandler.GetUserRooms).Methods("GET")
 	rooms.HandleFunc("/{id}", r.roomHandler.GetRoom).Methods("GET")
 	rooms.HandleFunc("/{id}/join", r.roomHandler.JoinRoom).Methods("POST")
 	rooms.HandleFunc("/{id}/leave", r.roomHandler.LeaveRoom).Methods("POST")
 	rooms.HandleFunc("/{id}", r.roomHandler.EndRoom).Methods("DELETE")
 	rooms.HandleFunc("/{id}/participants", r.roomHandler.GetParticipants).Methods("GET")
+	rooms.HandleFunc("/{id}/extend", r.roomHandler.ExtendRoom).Methods("POST")
+	rooms.HandleFunc("/{id}/attendance", r.roomHandler.GetAttendance).Methods("GET")
+	rooms.HandleFunc("/{id}/settings", r.roomHandler.UpdateSettings).Methods("PATCH")
+	rooms.HandleFunc("/{id}/legal-hold", r.roomHandler.SetLegalHold).Methods("PUT")
+
+	// Protected routes - Room templates
+	templates := api.PathPrefix("/templates").Subrouter()
+	templates.Use(r.authMiddleware.Authenticate)
+	templates.HandleFunc("", r.templateHandler.CreateTemplate).Methods("POST")
+	templates.HandleFunc("", r.templateHandler.ListTemplates).Methods("GET")
+	templates.HandleFunc("/{id}", r.templateHandler.GetTemplate).Methods("GET")
+	templates.HandleFunc("/{id}", r.templateHandler.UpdateTemplate).Methods("PUT")
+	templates.HandleFunc("/{id}", r.templateHandler.DeleteTemplate).Methods("DELETE")
 
 	// Protected routes - Chat
 	chat := api.PathPrefix("/rooms/{id}/messages").Subrouter()
-	chat.Use(r.authMiddleware.Authenticate)
+	chat.Use(r.authMiddleware.Authenticate, r.roomHandler.RoomsOnly)
 	chat.HandleFunc("", r.chatHandler.GetMessages).Methods("GET")
-
-	// WebSocket route
+	chat.HandleFunc("/export", r.chatHandler.ExportMessages).Methods("GET")
+	chat.HandleFunc("/pinned", r.chatHandler.GetPinnedMessages).Methods("GET")
+	chat.HandleFunc("/unread", r.chatHandler.GetUnread).Methods("GET")
+	chat.HandleFunc("/read", r.chatHandler.MarkRead).Methods("POST")
+	chat.HandleFunc("/{messageId}/thread", r.chatHandler.GetThread).Methods("GET")
+
+	messages := api.PathPrefix("/messages").Subrouter()
+	messages.Use(r.authMiddleware.Authenticate)
+	messages.HandleFunc("/search", r.chatHandler.SearchMessages).Methods("GET")
+
+	// Protected routes - Notifications
+	notifications := api.PathPrefix("/notifications").Subrouter()
+	notifications.Use(r.authMiddleware.Authenticate)
+	notifications.HandleFunc("", r.notificationHandler.ListNotifications).Methods("GET")
+	notifications.HandleFunc("/read-all", r.notificationHandler.MarkAllRead).Methods("POST")
+	notifications.HandleFunc("/{id}/read", r.notificationHandler.MarkRead).Methods("POST")
+
+	// Protected routes - Attachments
+	attachments := api.PathPrefix("/rooms/{id}/attachments").Subrouter()
+	attachments.Use(r.authMiddleware.Authenticate, r.roomHandler.RoomsOnly)
+	attachments.HandleFunc("", r.attachmentHandler.Upload).Methods("POST")
+	attachments.HandleFunc("/{attachmentId}", r.attachmentHandler.Download).Methods("GET")
+
+	// Protected routes - Channels
+	channels := api.PathPrefix("/channels").Subrouter()
+	channels.Use(r.authMiddleware.Authenticate)
+	channels.HandleFunc("", r.channelHandler.CreateChannel).Methods("POST")
+	channels.HandleFunc("", r.channelHandler.ListChannels).Methods("GET")
+	channels.HandleFunc("/{id}", r.channelHandler.GetChannel).Methods("GET")
+	channels.HandleFunc("/{id}/join", r.channelHandler.JoinChannel).Methods("POST")
+	channels.HandleFunc("/{id}/members", r.channelHandler.AddMember).Methods("POST")
+	channels.HandleFunc("/{id}/members/{userId}", r.channelHandler.RemoveMember).Methods("DELETE")
+	channels.HandleFunc("/{id}/calls", r.channelHandler.StartCall).Methods("POST")
+	channels.HandleFunc("/{id}/calls", r.channelHandler.ListCalls).Methods("GET")
+
+	// Channel chat is served by the chat handlers, for members only
+	members := r.channelHandler.MembersOnly
+	channels.HandleFunc("/{id}/messages", members(r.chatHandler.GetMessages)).Methods("GET")
+	channels.HandleFunc("/{id}/messages/export", members(r.chatHandler.ExportMessages)).Methods("GET")
+	channels.HandleFunc("/{id}/messages/pinned", members(r.chatHandler.GetPinnedMessages)).Methods("GET")
+	channels.HandleFunc("/{id}/messages/unread", members(r.chatHandler.GetUnread)).Methods("GET")
+	channels.HandleFunc("/{id}/messages/read", members(r.chatHandler.MarkRead)).Methods("POST")
+	channels.HandleFunc("/{id}/messages/{messageId}/thread", members(r.chatHandler.GetThread)).Methods("GET")
+	channels.HandleFunc("/{id}/attachments", members(r.attachmentHandler.Upload)).Methods("POST")
+	channels.HandleFunc("/{id}/attachments/{attachmentId}", members(r.attachmentHandler.Download)).Methods("GET")
+
+	// WebSocket routes
 	api.HandleFunc("/ws/room/{id}", r.wsHandler.HandleWebSocket)
+	api.HandleFunc("/ws/channel/{id}", r.wsHandler.HandleChannelWebSocket)
 
 	// Protected routes - Calls
 	calls := api.PathPrefix("/calls").Subrouter()
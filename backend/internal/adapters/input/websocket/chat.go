@@ -0,0 +1,188 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/chat"
+	"github.com/meet-clone/backend/internal/core/domain/command"
+)
+
+func (c *Client) handleChatMessage(hub *Hub, msg *Message) {
+	message := payloadString(msg, "message")
+	userName := payloadString(msg, "user_name")
+	attachmentID := payloadString(msg, "attachment_id")
+
+	if message == "" && attachmentID == "" {
+		return
+	}
+
+	// Commands only make sense as plain room messages
+	if _, _, ok := command.Parse(message); ok && attachmentID == "" && payloadString(msg, "recipient_id") == "" {
+		c.handleCommand(hub, msg, message, userName)
+		return
+	}
+
+	saved, err := hub.chatService.SendMessage(context.Background(), chat.Draft{
+		RoomID:       c.roomID,
+		UserID:       c.userID,
+		UserName:     userName,
+		Message:      message,
+		RecipientID:  payloadString(msg, "recipient_id"),
+		AttachmentID: attachmentID,
+		ClientMsgID:  payloadString(msg, "client_msg_id"),
+	})
+	if err != nil {
+		c.sendChatError(msg, err)
+		return
+	}
+
+	// Send the stored message so clients learn its ID
+	c.sendAck(msg.Type, saved, false)
+	hub.stopTyping(c.roomID, c.userID)
+	hub.publishChat("chat_message", saved)
+	hub.deliverMentions(saved)
+}
+
+// handleChatReply posts a reply in the thread of payload.parent_id, which
+// must be a message of the same room.
+func (c *Client) handleChatReply(hub *Hub, msg *Message) {
+	saved, err := hub.chatService.SendMessage(context.Background(), chat.Draft{
+		RoomID:       c.roomID,
+		UserID:       c.userID,
+		UserName:     payloadString(msg, "user_name"),
+		Message:      payloadString(msg, "message"),
+		ParentID:     payloadString(msg, "parent_id"),
+		Quote:        payloadBool(msg, "quote"),
+		AttachmentID: payloadString(msg, "attachment_id"),
+		ClientMsgID:  payloadString(msg, "client_msg_id"),
+	})
+	if err != nil {
+		c.sendChatError(msg, err)
+		return
+	}
+
+	c.sendAck(msg.Type, saved, false)
+	hub.stopTyping(c.roomID, c.userID)
+	hub.publishChat("chat_reply", saved)
+	hub.deliverMentions(saved)
+}
+
+func (c *Client) handleChatMessageEdit(hub *Hub, msg *Message) {
+	edited, err := hub.chatService.EditMessage(
+		context.Background(),
+		c.roomID,
+		payloadString(msg, "message_id"),
+		c.userID,
+		payloadString(msg, "message"),
+	)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.publishChat("chat_message_updated", edited)
+}
+
+func (c *Client) handleChatMessageDelete(hub *Hub, msg *Message) {
+	deleted, err := hub.chatService.DeleteMessage(
+		context.Background(),
+		c.roomID,
+		payloadString(msg, "message_id"),
+		c.userID,
+	)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.publishChat("chat_message_deleted", deleted)
+}
+
+// handleMarkRead moves the sender's read marker to payload.message_id and
+// shares the receipt with the room.
+func (c *Client) handleMarkRead(hub *Hub, msg *Message) {
+	marker, err := hub.chatService.MarkRead(context.Background(), c.roomID, c.userID, payloadString(msg, "message_id"))
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.PublishReadMarker(marker)
+}
+
+// PublishReadMarker tells the room that a user read up to a message. A
+// marker on a private message only reaches its audience, so others do not
+// learn the message exists.
+func (h *Hub) PublishReadMarker(marker *chat.ReadMarker) {
+	event := &Message{
+		Type:    "read_marker",
+		RoomID:  marker.RoomID,
+		UserID:  marker.UserID,
+		Payload: marker,
+	}
+
+	if marker.Audience == nil {
+		h.broadcast <- event
+		return
+	}
+
+	for _, userID := range marker.Audience {
+		h.sendToUser(marker.RoomID, userID, event)
+	}
+}
+
+// sendAck confirms to the sender that a message is stored. Duplicate acks
+// answer a resend of a message that was stored before.
+func (c *Client) sendAck(request string, saved *chat.Message, duplicate bool) {
+	c.sendEvent(&Message{
+		Type:   "ack",
+		RoomID: c.roomID,
+		UserID: c.userID,
+		Payload: map[string]interface{}{
+			"request":       request,
+			"client_msg_id": saved.ClientMsgID,
+			"message_id":    saved.ID,
+			"timestamp":     saved.Timestamp,
+			"duplicate":     duplicate,
+		},
+	})
+}
+
+// sendChatError answers a chat message that was not stored with an ack for
+// duplicates, or an error that carries the client message ID.
+func (c *Client) sendChatError(msg *Message, err error) {
+	if dup, ok := err.(*chat.Duplicate); ok {
+		c.sendAck(msg.Type, dup.Message, true)
+		return
+	}
+
+	payload := errorPayload(msg.Type, err)
+	if clientMsgID := payloadString(msg, "client_msg_id"); clientMsgID != "" {
+		payload["client_msg_id"] = clientMsgID
+	}
+	c.sendEvent(&Message{
+		Type:    "error",
+		RoomID:  c.roomID,
+		UserID:  c.userID,
+		Payload: payload,
+	})
+}
+
+// publishChat delivers a chat event about the message to everyone who can
+// see it: the whole room, or only both sides of a private message.
+func (h *Hub) publishChat(eventType string, msg *chat.Message) {
+	event := &Message{
+		Type:    eventType,
+		RoomID:  msg.RoomID,
+		UserID:  msg.UserID,
+		Payload: msg,
+	}
+
+	if !msg.IsPrivate() {
+		h.broadcast <- event
+		return
+	}
+
+	h.sendToUser(msg.RoomID, msg.UserID, event)
+	h.sendToUser(msg.RoomID, msg.RecipientID, event)
+}
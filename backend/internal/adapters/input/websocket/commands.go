@@ -0,0 +1,35 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/command"
+)
+
+// handleCommand runs a chat message starting with a slash instead of
+// storing it, and delivers the structured response as a command_response.
+func (c *Client) handleCommand(hub *Hub, msg *Message, text, userName string) {
+	resp, err := hub.commands.Execute(context.Background(), c.roomID, c.userID, userName, text)
+	if err != nil {
+		c.sendChatError(msg, err)
+		return
+	}
+
+	event := &Message{
+		Type:    "command_response",
+		RoomID:  c.roomID,
+		UserID:  c.userID,
+		Payload: resp,
+	}
+
+	hub.stopTyping(c.roomID, c.userID)
+	if resp.Audience == command.AudienceCaller {
+		c.sendEvent(event)
+	} else {
+		hub.broadcast <- event
+	}
+
+	if resp.Room != nil && len(resp.Affected) > 0 {
+		hub.broadcastModeration(resp.Room, resp.Affected...)
+	}
+}
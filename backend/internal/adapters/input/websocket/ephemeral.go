@@ -0,0 +1,103 @@
+package websocket
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ephemeralState holds short-lived per-user flags of rooms, such as who is
+// typing. Entries expire unless they are refreshed, so a client that
+// vanishes without clearing its flag does not leave it set forever.
+type ephemeralState struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]map[string]time.Time
+}
+
+func newEphemeralState(ttl time.Duration) *ephemeralState {
+	return &ephemeralState{
+		ttl:     ttl,
+		entries: make(map[string]map[string]time.Time),
+	}
+}
+
+// set flags the user until the TTL passes and reports whether the flag was
+// newly set rather than refreshed.
+func (e *ephemeralState) set(roomID, userID string, now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	users, ok := e.entries[roomID]
+	if !ok {
+		users = make(map[string]time.Time)
+		e.entries[roomID] = users
+	}
+
+	_, refreshed := users[userID]
+	users[userID] = now.Add(e.ttl)
+	return !refreshed
+}
+
+// clear removes the user's flag and reports whether it was set.
+func (e *ephemeralState) clear(roomID, userID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	users, ok := e.entries[roomID]
+	if !ok {
+		return false
+	}
+	if _, ok := users[userID]; !ok {
+		return false
+	}
+
+	delete(users, userID)
+	if len(users) == 0 {
+		delete(e.entries, roomID)
+	}
+	return true
+}
+
+// expire removes the flags that passed their TTL and returns their users
+// by room.
+func (e *ephemeralState) expire(now time.Time) map[string][]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	expired := make(map[string][]string)
+	for roomID, users := range e.entries {
+		for userID, expiresAt := range users {
+			if now.Before(expiresAt) {
+				continue
+			}
+			delete(users, userID)
+			expired[roomID] = append(expired[roomID], userID)
+		}
+		if len(users) == 0 {
+			delete(e.entries, roomID)
+		}
+	}
+	return expired
+}
+
+// users returns the flagged users of a room in a stable order.
+func (e *ephemeralState) users(roomID string) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	users := make([]string, 0, len(e.entries[roomID]))
+	for userID := range e.entries[roomID] {
+		users = append(users, userID)
+	}
+	sort.Strings(users)
+	return users
+}
+
+// forget drops every flag of the room.
+func (e *ephemeralState) forget(roomID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.entries, roomID)
+}
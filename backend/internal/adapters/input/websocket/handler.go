@@ -6,10 +6,16 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
+	"github.com/meet-clone/backend/internal/core/domain/channel"
 	"github.com/meet-clone/backend/internal/core/domain/chat"
+	"github.com/meet-clone/backend/internal/core/domain/command"
+	"github.com/meet-clone/backend/internal/core/domain/notification"
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/pkg/errors"
 	"github.com/meet-clone/backend/internal/pkg/jwt"
 )
 
@@ -26,15 +32,56 @@ type Client struct {
 	roomID string
 	userID string
 	send   chan []byte
+	// mu guards closed, so nothing is queued on send once it is closed.
+	mu     sync.Mutex
+	closed bool
+}
+
+// queue puts data on the client's send buffer without blocking. It reports
+// false when the buffer is full or the client is already closed.
+func (c *Client) queue(data []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes the send buffer, which stops writePump. Closing twice is
+// harmless.
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
 }
 
 type Hub struct {
 	rooms       map[string]map[*Client]bool
+	lastSeen    map[string]map[string]time.Time
 	broadcast   chan *Message
 	register    chan *Client
 	unregister  chan *Client
 	mu          sync.RWMutex
 	chatService chat.Service
+	roomService room.Service
+	// notifications keeps mentions for users who are not connected.
+	notifications notification.Service
+	reactions     *reactions
+	// typing holds who is typing in each room.
+	typing *ephemeralState
+	// commands runs chat messages that start with a slash.
+	commands command.Service
 }
 
 type Message struct {
@@ -44,17 +91,26 @@ type Message struct {
 	Payload interface{} `json:"payload"`
 }
 
-func NewHub(chatService chat.Service) *Hub {
+func NewHub(chatService chat.Service, roomService room.Service, notifications notification.Service, commands command.Service) *Hub {
 	return &Hub{
-		rooms:       make(map[string]map[*Client]bool),
-		broadcast:   make(chan *Message, 256),
-		register:    make(chan *Client),
-		unregister:  make(chan *Client),
-		chatService: chatService,
+		rooms:         make(map[string]map[*Client]bool),
+		lastSeen:      make(map[string]map[string]time.Time),
+		broadcast:     make(chan *Message, 256),
+		register:      make(chan *Client),
+		unregister:    make(chan *Client),
+		chatService:   chatService,
+		roomService:   roomService,
+		notifications: notifications,
+		reactions:     newReactions(),
+		typing:        newEphemeralState(typingTTL),
+		commands:      commands,
 	}
 }
 
 func (h *Hub) Run() {
+	go h.flushReactions()
+	go h.expireTyping()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -63,6 +119,7 @@ func (h *Hub) Run() {
 				h.rooms[client.roomID] = make(map[*Client]bool)
 			}
 			h.rooms[client.roomID][client] = true
+			h.forgetUser(client.roomID, client.userID)
 			h.mu.Unlock()
 
 			// Notify others about new participant
@@ -77,14 +134,23 @@ func (h *Hub) Run() {
 			if clients, ok := h.rooms[client.roomID]; ok {
 				if _, ok := clients[client]; ok {
 					delete(clients, client)
-					close(client.send)
+					client.close()
 					if len(clients) == 0 {
 						delete(h.rooms, client.roomID)
 					}
 				}
 			}
+			if _, ok := h.lastSeen[client.roomID]; !ok {
+				h.lastSeen[client.roomID] = make(map[string]time.Time)
+			}
+			h.lastSeen[client.roomID][client.userID] = time.Now()
 			h.mu.Unlock()
 
+			// A user who left cannot keep typing
+			if !h.IsConnected(client.roomID, client.userID) && h.typing.clear(client.roomID, client.userID) {
+				h.broadcastToRoom(client.roomID, typingEvent(client.roomID, client.userID, false))
+			}
+
 			// Notify others about participant leaving
 			h.broadcastToRoom(client.roomID, &Message{
 				Type:   "participant_left",
@@ -105,26 +171,132 @@ func (h *Hub) broadcastToRoom(roomID string, message *Message) {
 
 	data, _ := json.Marshal(message)
 	for client := range clients {
-		select {
-		case client.send <- data:
-		default:
+		if !client.queue(data) {
+			// Too slow to keep up, or already gone
 			h.mu.Lock()
 			delete(h.rooms[roomID], client)
 			h.mu.Unlock()
-			close(client.send)
+			client.close()
+		}
+	}
+}
+
+// Publish broadcasts an event to every client connected to the room.
+func (h *Hub) Publish(roomID, eventType string, payload interface{}) {
+	h.broadcast <- &Message{
+		Type:    eventType,
+		RoomID:  roomID,
+		Payload: payload,
+	}
+}
+
+// IsConnected reports whether the user has at least one open connection
+// to the room.
+func (h *Hub) IsConnected(roomID, userID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.rooms[roomID] {
+		if client.userID == userID {
+			return true
 		}
 	}
+	return false
+}
+
+// LastSeen returns when the user's last connection to the room closed, or
+// now if they are still connected.
+func (h *Hub) LastSeen(roomID, userID string) (time.Time, bool) {
+	if h.IsConnected(roomID, userID) {
+		return time.Now(), true
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seen, ok := h.lastSeen[roomID][userID]
+	return seen, ok
+}
+
+// Forget drops the presence history of the room.
+func (h *Hub) Forget(roomID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.lastSeen, roomID)
+	h.typing.forget(roomID)
+}
+
+// Prune drops the last seen times older than before, so users who never
+// come back do not stay in memory.
+func (h *Hub) Prune(before time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for roomID, users := range h.lastSeen {
+		for userID, seen := range users {
+			if seen.Before(before) {
+				h.forgetUser(roomID, userID)
+			}
+		}
+	}
+}
+
+// forgetUser drops the last seen time of a user, for example because they
+// reconnected. The caller holds h.mu.
+func (h *Hub) forgetUser(roomID, userID string) {
+	users, ok := h.lastSeen[roomID]
+	if !ok {
+		return
+	}
+	delete(users, userID)
+	if len(users) == 0 {
+		delete(h.lastSeen, roomID)
+	}
+}
+
+// Disconnect closes every connection the user has open in the room, for
+// example after they were removed from a channel.
+func (h *Hub) Disconnect(roomID, userID string) {
+	for _, client := range h.userClients(roomID, userID) {
+		client.conn.Close()
+	}
+}
+
+// sendToUser delivers a message to every connection the user has open in
+// the room. Sends go through Client.sendEvent, which is safe against
+// broadcastToRoom or unregister closing the client concurrently.
+func (h *Hub) sendToUser(roomID, userID string, message *Message) {
+	for _, client := range h.userClients(roomID, userID) {
+		client.sendEvent(message)
+	}
+}
+
+// userClients returns the connections the user has open in the room.
+func (h *Hub) userClients(roomID, userID string) []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var clients []*Client
+	for client := range h.rooms[roomID] {
+		if client.userID == userID {
+			clients = append(clients, client)
+		}
+	}
+	return clients
 }
 
 type Handler struct {
 	hub        *Hub
 	jwtService *jwt.JWTService
+	channels   channel.Service
 }
 
-func NewHandler(hub *Hub, jwtService *jwt.JWTService) *Handler {
+func NewHandler(hub *Hub, jwtService *jwt.JWTService, channels channel.Service) *Handler {
 	return &Handler{
 		hub:        hub,
 		jwtService: jwtService,
+		channels:   channels,
 	}
 }
 
@@ -141,6 +313,20 @@ func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Channels have their own endpoint, which checks channel membership
+	rm, err := h.hub.roomService.GetRoomDetails(r.Context(), roomID)
+	if err != nil {
+		http.Error(w, "room not found", http.StatusNotFound)
+		return
+	}
+
+	// Only people who joined the room receive its events, so users
+	// waiting in the lobby and strangers cannot listen in
+	if !rm.IsMember(claims.UserID) {
+		http.Error(w, "join the room first", http.StatusForbidden)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
@@ -155,6 +341,7 @@ func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.hub.register <- client
+	h.hub.sendSnapshot(client)
 
 	// Start goroutines for reading and writing
 	go client.writePump()
@@ -180,34 +367,196 @@ func (c *Client) readPump(hub *Hub) {
 		// Handle different message types
 		switch msg.Type {
 		case "chat_message":
-			if payload, ok := msg.Payload.(map[string]interface{}); ok {
-				message, _ := payload["message"].(string)
-				userName, _ := payload["user_name"].(string)
-
-				if message != "" {
-					_, err := hub.chatService.SendMessage(
-						context.Background(),
-						c.roomID,
-						c.userID,
-						userName,
-						message,
-					)
-					if err != nil {
-						log.Printf("Failed to save message: %v", err)
-						continue
-					}
+			c.handleChatMessage(hub, &msg)
+		case "chat_reply":
+			c.handleChatReply(hub, &msg)
+		case "pin_message":
+			c.handlePinMessage(hub, &msg)
+		case "unpin_message":
+			c.handleUnpinMessage(hub, &msg)
+		case "typing_start":
+			c.handleTypingStart(hub, &msg)
+		case "typing_stop":
+			c.handleTypingStop(hub, &msg)
+		case "mark_read":
+			c.handleMarkRead(hub, &msg)
+		case "chat_message_edit":
+			c.handleChatMessageEdit(hub, &msg)
+		case "chat_message_delete":
+			c.handleChatMessageDelete(hub, &msg)
+		case "raise_hand":
+			c.handleRaiseHand(hub, &msg)
+		case "lower_hand":
+			c.handleLowerHand(hub, &msg)
+		case "call_next_hand":
+			c.handleCallNextHand(hub, &msg)
+		case "reaction":
+			c.handleReaction(hub, &msg)
+		case "mute_participant":
+			c.handleMuteParticipant(hub, &msg)
+		case "mute_all":
+			c.handleMuteAll(hub, &msg)
+		case "block_unmute":
+			c.handleBlockUnmute(hub, &msg)
+		case "mute_chat":
+			c.handleMuteChat(hub, &msg)
+		case "unmute":
+			c.handleUnmute(hub, &msg)
+		case "request_camera_off":
+			c.handleTargetedRequest(hub, &msg, "camera_off_requested")
+		case "stop_screen_share":
+			c.handleTargetedRequest(hub, &msg, "screen_share_stopped")
+		case "spotlight_add":
+			c.handleSpotlightAdd(hub, &msg)
+		case "spotlight_remove":
+			c.handleSpotlightRemove(hub, &msg)
+		case "spotlight_clear":
+			c.handleSpotlightClear(hub, &msg)
+		case "lobby_admit":
+			c.handleLobbyAdmit(hub, &msg)
+		case "lobby_deny":
+			c.handleLobbyDeny(hub, &msg)
+		}
+	}
+}
 
-					// Broadcast to room
-					hub.broadcast <- &Message{
-						Type:    "chat_message",
-						RoomID:  c.roomID,
-						UserID:  c.userID,
-						Payload: payload,
-					}
-				}
-			}
+// sendEvent queues a message for this client only.
+func (c *Client) sendEvent(message *Message) {
+	data, _ := json.Marshal(message)
+	if !c.queue(data) {
+		log.Printf("Dropping %s event for user %s: connection closed or send buffer full", message.Type, c.userID)
+	}
+}
+
+// sendError reports a failed request back to the client that made it.
+func (c *Client) sendError(request string, err error) {
+	c.sendEvent(&Message{
+		Type:    "error",
+		RoomID:  c.roomID,
+		UserID:  c.userID,
+		Payload: errorPayload(request, err),
+	})
+}
+
+// errorPayload describes a failed request, with a type clients can act on.
+func errorPayload(request string, err error) map[string]interface{} {
+	payload := map[string]interface{}{
+		"request": request,
+		"message": err.Error(),
+	}
+	switch e := err.(type) {
+	case *errors.AppError:
+		payload["message"] = e.Message
+		payload["type"] = string(e.Type)
+	case *chat.Rejection:
+		// Moderation rejections carry a code the client can act on
+		payload["message"] = e.Message
+		payload["type"] = "REJECTED"
+		payload["code"] = string(e.Code)
+		if e.RetryAfter > 0 {
+			payload["retry_after_seconds"] = int64((e.RetryAfter + time.Second - 1) / time.Second)
 		}
 	}
+	return payload
+}
+
+// payloadString returns a string field of the message payload.
+func payloadString(msg *Message, key string) string {
+	payload, _ := msg.Payload.(map[string]interface{})
+	value, _ := payload[key].(string)
+	return value
+}
+
+// payloadBool returns a boolean field of the message payload.
+func payloadBool(msg *Message, key string) bool {
+	payload, _ := msg.Payload.(map[string]interface{})
+	value, _ := payload[key].(bool)
+	return value
+}
+
+// sendSnapshot sends the current room state to a newly connected client so
+// it can catch up on what happened before it joined.
+func (h *Hub) sendSnapshot(client *Client) {
+	rm, err := h.roomService.GetRoomDetails(context.Background(), client.roomID)
+	if err != nil {
+		log.Printf("Failed to load room %s for snapshot: %v", client.roomID, err)
+		return
+	}
+
+	pinned, err := h.chatService.GetPinnedMessages(context.Background(), client.roomID, client.userID)
+	if err != nil {
+		log.Printf("Failed to load pinned messages of room %s for snapshot: %v", client.roomID, err)
+		pinned = []*chat.Message{}
+	}
+
+	client.sendEvent(&Message{
+		Type:   "room_state",
+		RoomID: client.roomID,
+		Payload: map[string]interface{}{
+			"room":   rm,
+			"typing": h.typing.users(client.roomID),
+			"pinned": pinned,
+		},
+	})
+}
+
+// HandleChannelWebSocket connects a channel member to the channel's chat.
+// Channel events use the channel ID as their room ID.
+func (h *Handler) HandleChannelWebSocket(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	channelID := vars["id"]
+
+	token := r.URL.Query().Get("token")
+	claims, err := h.jwtService.ValidateToken(token)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ch, err := h.channels.GetChannel(r.Context(), channelID, claims.UserID)
+	if err != nil {
+		http.Error(w, "channel not found", http.StatusNotFound)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+
+	client := &Client{
+		conn:   conn,
+		roomID: ch.ID,
+		userID: claims.UserID,
+		send:   make(chan []byte, 256),
+	}
+
+	h.hub.register <- client
+	h.hub.sendChannelSnapshot(client, ch)
+
+	go client.writePump()
+	go client.readPump(h.hub)
+}
+
+// sendChannelSnapshot sends a joining channel member the channel with its
+// pinned messages and who is typing.
+func (h *Hub) sendChannelSnapshot(client *Client, ch *channel.Channel) {
+	pinned, err := h.chatService.GetPinnedMessages(context.Background(), ch.ID, client.userID)
+	if err != nil {
+		log.Printf("Failed to load pinned messages of channel %s for snapshot: %v", ch.ID, err)
+		pinned = []*chat.Message{}
+	}
+
+	client.sendEvent(&Message{
+		Type:   "channel_state",
+		RoomID: ch.ID,
+		Payload: map[string]interface{}{
+			"channel": ch,
+			"typing":  h.typing.users(ch.ID),
+			"pinned":  pinned,
+		},
+	})
 }
 
 func (c *Client) writePump() {
@@ -0,0 +1,60 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+func (c *Client) handleRaiseHand(hub *Hub, msg *Message) {
+	rm, err := hub.roomService.RaiseHand(context.Background(), c.roomID, c.userID)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastHandQueue(rm)
+}
+
+// handleLowerHand lowers the sender's hand, or the hand of payload.user_id
+// when a host lowers someone else's.
+func (c *Client) handleLowerHand(hub *Hub, msg *Message) {
+	targetID := payloadString(msg, "user_id")
+	if targetID == "" {
+		targetID = c.userID
+	}
+
+	rm, err := hub.roomService.LowerHand(context.Background(), c.roomID, c.userID, targetID)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastHandQueue(rm)
+}
+
+func (c *Client) handleCallNextHand(hub *Hub, msg *Message) {
+	rm, called, err := hub.roomService.CallNextHand(context.Background(), c.roomID, c.userID)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcast <- &Message{
+		Type:    "hand_called",
+		RoomID:  c.roomID,
+		UserID:  c.userID,
+		Payload: called,
+	}
+	hub.broadcastHandQueue(rm)
+}
+
+func (h *Hub) broadcastHandQueue(rm *room.Room) {
+	h.broadcast <- &Message{
+		Type:   "hand_queue_updated",
+		RoomID: rm.ID,
+		Payload: map[string]interface{}{
+			"queue": rm.HandQueue,
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+func (c *Client) handleLobbyAdmit(hub *Hub, msg *Message) {
+	rm, err := hub.roomService.AdmitFromLobby(context.Background(), c.roomID, c.userID, payloadString(msg, "user_id"))
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastLobby(rm)
+}
+
+func (c *Client) handleLobbyDeny(hub *Hub, msg *Message) {
+	rm, err := hub.roomService.DenyFromLobby(context.Background(), c.roomID, c.userID, payloadString(msg, "user_id"))
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastLobby(rm)
+}
+
+func (h *Hub) broadcastLobby(rm *room.Room) {
+	h.broadcast <- &Message{
+		Type:   "lobby_updated",
+		RoomID: rm.ID,
+		Payload: map[string]interface{}{
+			"lobby": rm.Lobby,
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package websocket
+
+import (
+	"context"
+	"log"
+
+	"github.com/meet-clone/backend/internal/core/domain/chat"
+	"github.com/meet-clone/backend/internal/core/domain/notification"
+)
+
+// deliverMentions sends a mention event to the connections of every user
+// mentioned in the message, and leaves a notification for those who are
+// not connected.
+func (h *Hub) deliverMentions(msg *chat.Message) {
+	for _, m := range msg.Mentions {
+		if h.IsConnected(msg.RoomID, m.UserID) {
+			h.sendToUser(msg.RoomID, m.UserID, &Message{
+				Type:    "mention",
+				RoomID:  msg.RoomID,
+				UserID:  msg.UserID,
+				Payload: msg,
+			})
+			continue
+		}
+
+		_, err := h.notifications.NotifyMention(context.Background(), notification.Mention{
+			UserID:    m.UserID,
+			RoomID:    msg.RoomID,
+			MessageID: msg.ID,
+			ActorID:   msg.UserID,
+			ActorName: msg.UserName,
+			Text:      msg.Message,
+		})
+		if err != nil {
+			log.Printf("Failed to record mention of user %s in room %s: %v", m.UserID, msg.RoomID, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+// moderationState is the host-controlled media state of a participant.
+type moderationState struct {
+	UserID        string `json:"user_id"`
+	MutedByHost   bool   `json:"muted_by_host"`
+	UnmuteBlocked bool   `json:"unmute_blocked"`
+	ChatMuted     bool   `json:"chat_muted"`
+}
+
+func (c *Client) handleMuteParticipant(hub *Hub, msg *Message) {
+	targetID := payloadString(msg, "user_id")
+	blockUnmute := payloadBool(msg, "block_unmute")
+
+	rm, err := hub.roomService.MuteParticipant(context.Background(), c.roomID, c.userID, targetID, blockUnmute)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.forceMute(rm, c.userID, targetID)
+	hub.broadcastModeration(rm, targetID)
+}
+
+func (c *Client) handleMuteAll(hub *Hub, msg *Message) {
+	blockUnmute := payloadBool(msg, "block_unmute")
+
+	rm, muted, err := hub.roomService.MuteAll(context.Background(), c.roomID, c.userID, blockUnmute)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	for _, userID := range muted {
+		hub.forceMute(rm, c.userID, userID)
+	}
+	hub.broadcastModeration(rm, muted...)
+}
+
+// handleBlockUnmute blocks or, with blocked set to false, allows unmuting.
+func (c *Client) handleBlockUnmute(hub *Hub, msg *Message) {
+	targetID := payloadString(msg, "user_id")
+	blocked := payloadBool(msg, "blocked")
+
+	rm, err := hub.roomService.SetUnmuteBlocked(context.Background(), c.roomID, c.userID, targetID, blocked)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.sendToUser(c.roomID, targetID, &Message{
+		Type:   "unmute_blocked",
+		RoomID: c.roomID,
+		UserID: c.userID,
+		Payload: map[string]bool{
+			"blocked": blocked,
+		},
+	})
+	hub.broadcastModeration(rm, targetID)
+}
+
+// handleMuteChat stops or, with muted set to false, allows a participant
+// sending chat messages.
+func (c *Client) handleMuteChat(hub *Hub, msg *Message) {
+	targetID := payloadString(msg, "user_id")
+	muted := payloadBool(msg, "muted")
+
+	rm, err := hub.roomService.SetChatMuted(context.Background(), c.roomID, c.userID, targetID, muted)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastModeration(rm, targetID)
+}
+
+// handleUnmute lets a participant muted by a host unmute themselves, unless
+// unmuting is blocked.
+func (c *Client) handleUnmute(hub *Hub, msg *Message) {
+	rm, err := hub.roomService.Unmute(context.Background(), c.roomID, c.userID)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastModeration(rm, c.userID)
+}
+
+// handleTargetedRequest forwards a host request that the target's client
+// has to carry out, such as turning the camera off.
+func (c *Client) handleTargetedRequest(hub *Hub, msg *Message, eventType string) {
+	targetID := payloadString(msg, "user_id")
+
+	if err := hub.roomService.AuthorizeModeration(context.Background(), c.roomID, c.userID, targetID); err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.sendToUser(c.roomID, targetID, &Message{
+		Type:   eventType,
+		RoomID: c.roomID,
+		UserID: c.userID,
+	})
+}
+
+func (h *Hub) forceMute(rm *room.Room, hostID, userID string) {
+	state := participantModeration(rm, userID)
+	h.sendToUser(rm.ID, userID, &Message{
+		Type:   "force_mute",
+		RoomID: rm.ID,
+		UserID: hostID,
+		Payload: map[string]bool{
+			"unmute_blocked": state.UnmuteBlocked,
+		},
+	})
+}
+
+func (h *Hub) broadcastModeration(rm *room.Room, userIDs ...string) {
+	states := make([]moderationState, 0, len(userIDs))
+	for _, userID := range userIDs {
+		states = append(states, participantModeration(rm, userID))
+	}
+
+	h.broadcast <- &Message{
+		Type:   "moderation_updated",
+		RoomID: rm.ID,
+		Payload: map[string]interface{}{
+			"participants": states,
+		},
+	}
+}
+
+func participantModeration(rm *room.Room, userID string) moderationState {
+	for _, p := range rm.GetActiveParticipants() {
+		if p.UserID == userID {
+			return moderationState{
+				UserID:        p.UserID,
+				MutedByHost:   p.MutedByHost,
+				UnmuteBlocked: p.UnmuteBlocked,
+				ChatMuted:     p.ChatMuted,
+			}
+		}
+	}
+	return moderationState{UserID: userID}
+}
@@ -0,0 +1,37 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/chat"
+)
+
+func (c *Client) handlePinMessage(hub *Hub, msg *Message) {
+	pinned, err := hub.chatService.PinMessage(context.Background(), c.roomID, payloadString(msg, "message_id"), c.userID)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastPins(c.roomID, pinned)
+}
+
+func (c *Client) handleUnpinMessage(hub *Hub, msg *Message) {
+	pinned, err := hub.chatService.UnpinMessage(context.Background(), c.roomID, payloadString(msg, "message_id"), c.userID)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastPins(c.roomID, pinned)
+}
+
+func (h *Hub) broadcastPins(roomID string, pinned []*chat.Message) {
+	h.broadcast <- &Message{
+		Type:   "pins_updated",
+		RoomID: roomID,
+		Payload: map[string]interface{}{
+			"pinned": pinned,
+		},
+	}
+}
@@ -0,0 +1,153 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+const (
+	// reactionBurst is how many reactions a user can send at once.
+	reactionBurst = 5
+	// reactionRefill is how long it takes to earn back one reaction.
+	reactionRefill = 500 * time.Millisecond
+	// reactionFlushInterval is how often aggregated reactions are sent to
+	// the room and recorded.
+	reactionFlushInterval = 500 * time.Millisecond
+	// reactionLimiterIdle is how long an unused rate limiter is kept.
+	reactionLimiterIdle = time.Minute
+)
+
+// reactionBucket is a token bucket limiting the reactions of one user.
+type reactionBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// reactions throttles reactions per user and aggregates them per room, so
+// a burst of reactions becomes one broadcast per flush interval.
+type reactions struct {
+	mu      sync.Mutex
+	buckets map[string]*reactionBucket
+	pending map[string]map[string]int
+}
+
+func newReactions() *reactions {
+	return &reactions{
+		buckets: make(map[string]*reactionBucket),
+		pending: make(map[string]map[string]int),
+	}
+}
+
+// allow takes a token from the user's bucket.
+func (r *reactions) allow(roomID, userID string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := roomID + "/" + userID
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &reactionBucket{tokens: reactionBurst, last: now}
+		r.buckets[key] = b
+	}
+
+	b.tokens += float64(now.Sub(b.last)) / float64(reactionRefill)
+	if b.tokens > reactionBurst {
+		b.tokens = reactionBurst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (r *reactions) add(roomID, emoji string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pending[roomID]; !ok {
+		r.pending[roomID] = make(map[string]int)
+	}
+	r.pending[roomID][emoji]++
+}
+
+// take returns and clears the reactions aggregated since the last flush,
+// and drops rate limiters that have been idle for a while.
+func (r *reactions) take(now time.Time) map[string]map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := r.pending
+	r.pending = make(map[string]map[string]int)
+
+	for key, b := range r.buckets {
+		if now.Sub(b.last) > reactionLimiterIdle {
+			delete(r.buckets, key)
+		}
+	}
+
+	return pending
+}
+
+func (c *Client) handleReaction(hub *Hub, msg *Message) {
+	emoji := payloadString(msg, "emoji")
+	if emoji == "" {
+		return
+	}
+
+	// Throttled reactions are dropped silently, before the room lookup so
+	// a flood of reactions does not turn into a flood of reads
+	if !hub.reactions.allow(c.roomID, c.userID, time.Now()) {
+		return
+	}
+
+	// Reject reactions from people who are not in the room and on rooms
+	// that ended
+	rm, err := hub.roomService.GetRoomDetails(context.Background(), c.roomID)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+	if !rm.IsActive() {
+		c.sendError(msg.Type, errors.NewValidationError("room has ended"))
+		return
+	}
+	if !rm.IsParticipant(c.userID) {
+		c.sendError(msg.Type, errors.NewForbiddenError("only participants can react"))
+		return
+	}
+	if !rm.Settings.AllowsReaction(emoji) {
+		return
+	}
+
+	hub.reactions.add(c.roomID, emoji)
+}
+
+// flushReactions broadcasts and records the aggregated reactions of every
+// room once per flush interval.
+func (h *Hub) flushReactions() {
+	ticker := time.NewTicker(reactionFlushInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		for roomID, counts := range h.reactions.take(now) {
+			h.broadcast <- &Message{
+				Type:   "reactions",
+				RoomID: roomID,
+				Payload: map[string]interface{}{
+					"counts": counts,
+				},
+			}
+
+			if err := h.roomService.RecordReactions(context.Background(), roomID, counts); err != nil {
+				log.Printf("Failed to record reactions for room %s: %v", roomID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+func (c *Client) handleSpotlightAdd(hub *Hub, msg *Message) {
+	rm, err := hub.roomService.AddSpotlight(context.Background(), c.roomID, c.userID, payloadString(msg, "user_id"))
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastSpotlight(rm)
+}
+
+func (c *Client) handleSpotlightRemove(hub *Hub, msg *Message) {
+	rm, err := hub.roomService.RemoveSpotlight(context.Background(), c.roomID, c.userID, payloadString(msg, "user_id"))
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastSpotlight(rm)
+}
+
+func (c *Client) handleSpotlightClear(hub *Hub, msg *Message) {
+	rm, err := hub.roomService.ClearSpotlight(context.Background(), c.roomID, c.userID)
+	if err != nil {
+		c.sendError(msg.Type, err)
+		return
+	}
+
+	hub.broadcastSpotlight(rm)
+}
+
+func (h *Hub) broadcastSpotlight(rm *room.Room) {
+	h.broadcast <- &Message{
+		Type:   "spotlight_updated",
+		RoomID: rm.ID,
+		Payload: map[string]interface{}{
+			"spotlight": rm.Spotlight,
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package websocket
+
+import "time"
+
+const (
+	// typingTTL is how long a typing_start lasts. Clients repeat it while
+	// the user keeps typing.
+	typingTTL = 6 * time.Second
+	// typingSweepInterval is how often expired typing indicators are
+	// cleared.
+	typingSweepInterval = time.Second
+)
+
+func (c *Client) handleTypingStart(hub *Hub, msg *Message) {
+	if hub.typing.set(c.roomID, c.userID, time.Now()) {
+		hub.broadcastTyping(c.roomID, c.userID, true)
+	}
+}
+
+func (c *Client) handleTypingStop(hub *Hub, msg *Message) {
+	hub.stopTyping(c.roomID, c.userID)
+}
+
+// stopTyping clears the user's typing indicator and tells the room if it
+// was shown.
+func (h *Hub) stopTyping(roomID, userID string) {
+	if h.typing.clear(roomID, userID) {
+		h.broadcastTyping(roomID, userID, false)
+	}
+}
+
+func (h *Hub) broadcastTyping(roomID, userID string, typing bool) {
+	h.broadcast <- typingEvent(roomID, userID, typing)
+}
+
+func typingEvent(roomID, userID string, typing bool) *Message {
+	eventType := "typing_stop"
+	payload := map[string]interface{}{
+		"user_id": userID,
+	}
+	if typing {
+		eventType = "typing_start"
+		payload["expires_in_seconds"] = int64(typingTTL / time.Second)
+	}
+
+	return &Message{
+		Type:    eventType,
+		RoomID:  roomID,
+		UserID:  userID,
+		Payload: payload,
+	}
+}
+
+// expireTyping clears the typing indicators of clients that stopped
+// refreshing them.
+func (h *Hub) expireTyping() {
+	ticker := time.NewTicker(typingSweepInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		for roomID, users := range h.typing.expire(now) {
+			for _, userID := range users {
+				h.broadcastTyping(roomID, userID, false)
+			}
+		}
+	}
+}
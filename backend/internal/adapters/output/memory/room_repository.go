@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+// RoomRepository is an in-memory room.Repository. It stores copies of the
+// rooms it is given so callers cannot mutate stored state without Update.
+type RoomRepository struct {
+	mu        sync.RWMutex
+	rooms     map[string]*room.Room
+	reactions map[string]map[string]int
+}
+
+func NewRoomRepository() *RoomRepository {
+	return &RoomRepository{
+		rooms:     make(map[string]*room.Room),
+		reactions: make(map[string]map[string]int),
+	}
+}
+
+func (r *RoomRepository) Create(ctx context.Context, rm *room.Room) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rooms[rm.ID]; ok {
+		return &room.RoomError{Message: "room already exists"}
+	}
+	r.rooms[rm.ID] = copyRoom(rm)
+	return nil
+}
+
+func (r *RoomRepository) FindByID(ctx context.Context, id string) (*room.Room, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rm, ok := r.rooms[id]
+	if !ok {
+		return nil, &room.RoomError{Message: "room not found"}
+	}
+	return copyRoom(rm), nil
+}
+
+func (r *RoomRepository) FindByCreator(ctx context.Context, createdBy string) ([]*room.Room, error) {
+	return r.find(func(rm *room.Room) bool {
+		return rm.CreatedBy == createdBy && rm.IsActive()
+	}, 0, 0), nil
+}
+
+func (r *RoomRepository) Update(ctx context.Context, rm *room.Room) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.rooms[rm.ID]
+	if !ok || stored.Version != rm.Version {
+		return room.ErrVersionConflict
+	}
+
+	rm.Version++
+	r.rooms[rm.ID] = copyRoom(rm)
+	return nil
+}
+
+func (r *RoomRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.rooms, id)
+	return nil
+}
+
+func (r *RoomRepository) FindActiveRooms(ctx context.Context, limit, offset int) ([]*room.Room, error) {
+	return r.find(func(rm *room.Room) bool {
+		return rm.IsActive()
+	}, limit, offset), nil
+}
+
+func (r *RoomRepository) List(ctx context.Context, filter room.ListFilter) ([]*room.Room, error) {
+	query := strings.ToLower(filter.Query)
+
+	rooms := r.find(func(rm *room.Room) bool {
+		if filter.ViewerID != "" && rm.CreatedBy != filter.ViewerID && !hasParticipant(rm, filter.ViewerID) {
+			return false
+		}
+		if filter.Status != "" && rm.Status != filter.Status {
+			return false
+		}
+		if filter.CreatedBy != "" && rm.CreatedBy != filter.CreatedBy {
+			return false
+		}
+		if filter.ParticipantID != "" && !hasParticipant(rm, filter.ParticipantID) {
+			return false
+		}
+		if !filter.CreatedAfter.IsZero() && rm.CreatedAt.Before(filter.CreatedAfter) {
+			return false
+		}
+		if !filter.CreatedBefore.IsZero() && !rm.CreatedAt.Before(filter.CreatedBefore) {
+			return false
+		}
+		if query != "" && !strings.Contains(strings.ToLower(rm.Title), query) {
+			return false
+		}
+		if filter.ChannelID != "" && rm.ChannelID != filter.ChannelID {
+			return false
+		}
+		if filter.WithChat && !rm.ChatPurgedAt.IsZero() {
+			return false
+		}
+		return true
+	}, 0, 0)
+
+	// Order by creation time and ID, the same way cursors are built
+	oldest := filter.Sort == room.SortOldest
+	sort.Slice(rooms, func(i, j int) bool {
+		a, b := rooms[i], rooms[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt) == oldest
+		}
+		return (a.ID < b.ID) == oldest
+	})
+
+	if filter.After != nil {
+		after := *filter.After
+		start := len(rooms)
+		for i, rm := range rooms {
+			var past bool
+			if rm.CreatedAt.Equal(after.CreatedAt) {
+				past = rm.ID != after.ID && (rm.ID > after.ID) == oldest
+			} else {
+				past = rm.CreatedAt.After(after.CreatedAt) == oldest
+			}
+			if past {
+				start = i
+				break
+			}
+		}
+		rooms = rooms[start:]
+	}
+
+	if filter.Limit > 0 && filter.Limit < len(rooms) {
+		rooms = rooms[:filter.Limit]
+	}
+	return rooms, nil
+}
+
+func (r *RoomRepository) AddReactions(ctx context.Context, roomID string, counts map[string]int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.reactions[roomID]; !ok {
+		r.reactions[roomID] = make(map[string]int)
+	}
+	for emoji, count := range counts {
+		r.reactions[roomID][emoji] += count
+	}
+	return nil
+}
+
+func (r *RoomRepository) FindReactions(ctx context.Context, roomID string) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int, len(r.reactions[roomID]))
+	for emoji, count := range r.reactions[roomID] {
+		counts[emoji] = count
+	}
+	return counts, nil
+}
+
+func hasParticipant(rm *room.Room, userID string) bool {
+	for _, p := range rm.Participants {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns copies of the matching rooms, newest first.
+func (r *RoomRepository) find(match func(rm *room.Room) bool, limit, offset int) []*room.Room {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rooms := []*room.Room{}
+	for _, rm := range r.rooms {
+		if match(rm) {
+			rooms = append(rooms, copyRoom(rm))
+		}
+	}
+
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].CreatedAt.After(rooms[j].CreatedAt)
+	})
+
+	if offset >= len(rooms) {
+		return []*room.Room{}
+	}
+	rooms = rooms[offset:]
+	if limit > 0 && limit < len(rooms) {
+		rooms = rooms[:limit]
+	}
+	return rooms
+}
+
+func copyRoom(rm *room.Room) *room.Room {
+	c := *rm
+	c.Participants = make([]room.Participant, len(rm.Participants))
+	for i, p := range rm.Participants {
+		p.Sessions = append([]room.Session(nil), p.Sessions...)
+		c.Participants[i] = p
+	}
+	c.HandQueue = append([]room.RaisedHand(nil), rm.HandQueue...)
+	c.Spotlight = append([]string(nil), rm.Spotlight...)
+	c.Lobby = append([]room.LobbyEntry(nil), rm.Lobby...)
+	c.Settings.AllowedReactions = append([]string(nil), rm.Settings.AllowedReactions...)
+	return &c
+}
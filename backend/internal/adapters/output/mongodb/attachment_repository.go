@@ -0,0 +1,70 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/meet-clone/backend/internal/core/domain/attachment"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AttachmentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAttachmentRepository(client *Client) attachment.Repository {
+	return &AttachmentRepository{
+		collection: client.GetCollection("attachments"),
+	}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, a *attachment.Attachment) error {
+	_, err := r.collection.InsertOne(ctx, a)
+	return err
+}
+
+func (r *AttachmentRepository) FindByID(ctx context.Context, id string) (*attachment.Attachment, error) {
+	var a attachment.Attachment
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&a)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AttachmentRepository) FindCreatedBefore(ctx context.Context, before time.Time, limit, offset int) ([]*attachment.Attachment, error) {
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset)).
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	return r.find(ctx, bson.M{"created_at": bson.M{"$lt": before}}, opts)
+}
+
+func (r *AttachmentRepository) FindByRoomID(ctx context.Context, roomID string, limit int) ([]*attachment.Attachment, error) {
+	opts := options.Find().SetLimit(int64(limit))
+
+	return r.find(ctx, bson.M{"room_id": roomID}, opts)
+}
+
+func (r *AttachmentRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*attachment.Attachment, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	attachments := []*attachment.Attachment{}
+	if err := cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
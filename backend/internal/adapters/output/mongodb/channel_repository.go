@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/channel"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ChannelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewChannelRepository(client *Client) channel.Repository {
+	return &ChannelRepository{
+		collection: client.GetCollection("channels"),
+	}
+}
+
+func (r *ChannelRepository) Create(ctx context.Context, ch *channel.Channel) error {
+	_, err := r.collection.InsertOne(ctx, ch)
+	return err
+}
+
+func (r *ChannelRepository) FindByID(ctx context.Context, id string) (*channel.Channel, error) {
+	var ch channel.Channel
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&ch)
+	if err != nil {
+		return nil, err
+	}
+	return &ch, nil
+}
+
+func (r *ChannelRepository) FindVisible(ctx context.Context, userID, organizationID string) ([]*channel.Channel, error) {
+	filter := bson.M{"members.user_id": userID}
+	if organizationID != "" {
+		filter = bson.M{"$or": bson.A{
+			bson.M{"members.user_id": userID},
+			bson.M{"organization_id": organizationID},
+		}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"name": 1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	channels := []*channel.Channel{}
+	if err := cursor.All(ctx, &channels); err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+func (r *ChannelRepository) AddMember(ctx context.Context, channelID string, member channel.Member) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": channelID, "members.user_id": bson.M{"$ne": member.UserID}},
+		bson.M{"$push": bson.M{"members": member}},
+	)
+	return err
+}
+
+func (r *ChannelRepository) RemoveMember(ctx context.Context, channelID, userID string) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": channelID},
+		bson.M{"$pull": bson.M{"members": bson.M{"user_id": userID}}},
+	)
+	return err
+}
@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"time"
 
 	"github.com/meet-clone/backend/internal/core/domain/chat"
 	"go.mongodb.org/mongo-driver/bson"
@@ -21,16 +22,47 @@ func NewChatRepository(client *Client) chat.Repository {
 
 func (r *ChatRepository) Create(ctx context.Context, message *chat.Message) error {
 	_, err := r.collection.InsertOne(ctx, message)
+	if mongo.IsDuplicateKeyError(err) && message.ClientMsgID != "" {
+		return chat.ErrDuplicateClientMsgID
+	}
 	return err
 }
 
-func (r *ChatRepository) FindByRoomID(ctx context.Context, roomID string, limit, offset int) ([]*chat.Message, error) {
+func (r *ChatRepository) FindByClientMsgID(ctx context.Context, roomID, userID, clientMsgID string) (*chat.Message, error) {
+	var msg chat.Message
+	err := r.collection.FindOne(ctx, bson.M{
+		"room_id":       roomID,
+		"user_id":       userID,
+		"client_msg_id": clientMsgID,
+	}).Decode(&msg)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (r *ChatRepository) FindByID(ctx context.Context, id string) (*chat.Message, error) {
+	var msg chat.Message
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&msg)
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (r *ChatRepository) FindByRoomID(ctx context.Context, roomID, viewerID string, limit, offset int) ([]*chat.Message, error) {
 	opts := options.Find().
 		SetLimit(int64(limit)).
 		SetSkip(int64(offset)).
 		SetSort(bson.M{"timestamp": -1})
 
-	cursor, err := r.collection.Find(ctx, bson.M{"room_id": roomID}, opts)
+	filter := visibleTo(viewerID)
+	filter["room_id"] = roomID
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +81,257 @@ func (r *ChatRepository) FindByRoomID(ctx context.Context, roomID string, limit,
 	return messages, nil
 }
 
+func (r *ChatRepository) UpdateContent(ctx context.Context, id, message string, previous chat.Revision) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deleted": bson.M{"$ne": true}},
+		bson.M{
+			"$set":  bson.M{"message": message, "edited_at": previous.ReplacedAt},
+			"$push": bson.M{"history": previous},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *ChatRepository) MarkDeleted(ctx context.Context, id, deletedBy string, deletedAt time.Time) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{
+				"deleted":    true,
+				"deleted_at": deletedAt,
+				"deleted_by": deletedBy,
+			},
+			"$unset": unpinFields,
+		},
+	)
+	return err
+}
+
+func (r *ChatRepository) FindReplies(ctx context.Context, parentID string) ([]*chat.Message, error) {
+	opts := options.Find().
+		SetSort(bson.M{"timestamp": 1})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"parent_id": parentID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []*chat.Message{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *ChatRepository) IncrementReplyCount(ctx context.Context, id string, delta int) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"reply_count": delta}},
+	)
+	return err
+}
+
+// unpinFields clears the pin of a message, including the slot it held.
+var unpinFields = bson.M{"pinned": "", "pinned_at": "", "pinned_by": "", "pin_slot": ""}
+
+// Pin takes the first free pin slot of the room. A unique index on the
+// room and slot makes concurrent pins fail over to the next slot, so no
+// more than maxPins messages hold one.
+func (r *ChatRepository) Pin(ctx context.Context, id, pinnedBy string, pinnedAt time.Time, maxPins int) error {
+	filter := bson.M{"_id": id, "pinned": bson.M{"$ne": true}, "deleted": bson.M{"$ne": true}}
+	for slot := 0; slot < maxPins; slot++ {
+		result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+			"pinned":    true,
+			"pinned_at": pinnedAt,
+			"pinned_by": pinnedBy,
+			"pin_slot":  slot,
+		}})
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return chat.ErrNotPinnable
+		}
+		return nil
+	}
+	return chat.ErrPinLimit
+}
+
+func (r *ChatRepository) Unpin(ctx context.Context, id string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$unset": unpinFields})
+	return err
+}
+
+func (r *ChatRepository) FindPinned(ctx context.Context, roomID string) ([]*chat.Message, error) {
+	opts := options.Find().
+		SetSort(bson.M{"pinned_at": 1})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"room_id": roomID, "pinned": true}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []*chat.Message{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
 func (r *ChatRepository) DeleteByRoomID(ctx context.Context, roomID string) error {
 	_, err := r.collection.DeleteMany(ctx, bson.M{"room_id": roomID})
 	return err
 }
+
+func (r *ChatRepository) Search(ctx context.Context, search chat.MessageSearch) ([]*chat.Message, error) {
+	conditions := bson.A{
+		bson.M{"$text": bson.M{"$search": search.Text}},
+		bson.M{"room_id": bson.M{"$in": search.RoomIDs}},
+		bson.M{"deleted": bson.M{"$ne": true}},
+		visibleTo(search.ViewerID),
+	}
+	if !search.From.IsZero() {
+		conditions = append(conditions, bson.M{"timestamp": bson.M{"$gte": search.From}})
+	}
+	if !search.To.IsZero() {
+		conditions = append(conditions, bson.M{"timestamp": bson.M{"$lt": search.To}})
+	}
+	if search.After != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"timestamp": bson.M{"$lt": search.After.Timestamp}},
+			bson.M{"timestamp": search.After.Timestamp, "_id": bson.M{"$lt": search.After.ID}},
+		}})
+	}
+
+	opts := options.Find().
+		SetLimit(int64(search.Limit)).
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []*chat.Message{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// visibleTo matches the public messages and the private messages the user
+// sent or received.
+func visibleTo(userID string) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"recipient_id": nil},
+		bson.M{"user_id": userID},
+		bson.M{"recipient_id": userID},
+	}}
+}
+
+func (r *ChatRepository) Stream(ctx context.Context, roomID, viewerID string, fn func(msg *chat.Message) error) error {
+	filter := visibleTo(viewerID)
+	filter["room_id"] = roomID
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var msg chat.Message
+		if err := cursor.Decode(&msg); err != nil {
+			return err
+		}
+		if err := fn(&msg); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func (r *ChatRepository) FindPage(ctx context.Context, query chat.MessageQuery) ([]*chat.Message, error) {
+	conditions := bson.A{
+		bson.M{"room_id": query.RoomID},
+		visibleTo(query.ViewerID),
+	}
+
+	// Walk away from the cursor: backwards by default, forwards after it
+	direction := -1
+	if query.After != nil {
+		direction = 1
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"timestamp": bson.M{"$gt": query.After.Timestamp}},
+			bson.M{"timestamp": query.After.Timestamp, "_id": bson.M{"$gt": query.After.ID}},
+		}})
+	}
+	if query.Before != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"timestamp": bson.M{"$lt": query.Before.Timestamp}},
+			bson.M{"timestamp": query.Before.Timestamp, "_id": bson.M{"$lt": query.Before.ID}},
+		}})
+	}
+
+	opts := options.Find().
+		SetLimit(int64(query.Limit)).
+		SetSort(bson.D{{Key: "timestamp", Value: direction}, {Key: "_id", Value: direction}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []*chat.Message{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	if direction < 0 {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+
+	return messages, nil
+}
+
+func (r *ChatRepository) CountUnread(ctx context.Context, roomID, viewerID string, after *chat.Cursor) (int64, error) {
+	conditions := bson.A{
+		bson.M{"room_id": roomID},
+		bson.M{"user_id": bson.M{"$ne": viewerID}},
+		bson.M{"deleted": bson.M{"$ne": true}},
+		visibleTo(viewerID),
+	}
+	if after != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"timestamp": bson.M{"$gt": after.Timestamp}},
+			bson.M{"timestamp": after.Timestamp, "_id": bson.M{"$gt": after.ID}},
+		}})
+	}
+
+	return r.collection.CountDocuments(ctx, bson.M{"$and": conditions})
+}
@@ -66,22 +66,118 @@ func (c *Client) CreateIndexes(ctx context.Context) error {
 			Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
 		},
 		{
-			Keys: bson.D{{Key: "created_by", Value: 1}},
+			Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "participants.user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}},
+		},
+		{
+			Keys:    bson.D{{Key: "channel_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetSparse(true),
 		},
 	}
 	if _, err := c.db.Collection("rooms").Indexes().CreateMany(ctx, roomIndexes); err != nil {
 		return err
 	}
 
+	// Template indexes
+	templateIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "owner_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "organization_id", Value: 1}},
+		},
+	}
+	if _, err := c.db.Collection("room_templates").Indexes().CreateMany(ctx, templateIndexes); err != nil {
+		return err
+	}
+
 	// Message indexes
 	messageIndexes := []mongo.IndexModel{
 		{
 			Keys: bson.D{{Key: "room_id", Value: 1}, {Key: "timestamp", Value: -1}},
 		},
+		{
+			Keys:    bson.D{{Key: "parent_id", Value: 1}, {Key: "timestamp", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "message", Value: "text"}},
+		},
+		{
+			Keys:    bson.D{{Key: "room_id", Value: 1}, {Key: "pinned_at", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"pinned": true}),
+		},
+		{
+			Keys: bson.D{{Key: "room_id", Value: 1}, {Key: "pin_slot", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"pin_slot": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "room_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "client_msg_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"client_msg_id": bson.M{"$exists": true}}),
+		},
 	}
 	if _, err := c.db.Collection("chat_messages").Indexes().CreateMany(ctx, messageIndexes); err != nil {
 		return err
 	}
 
+	// Read marker indexes
+	markerIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "room_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := c.db.Collection("chat_read_markers").Indexes().CreateMany(ctx, markerIndexes); err != nil {
+		return err
+	}
+
+	// Channel indexes
+	channelIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "members.user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "organization_id", Value: 1}},
+		},
+	}
+	if _, err := c.db.Collection("channels").Indexes().CreateMany(ctx, channelIndexes); err != nil {
+		return err
+	}
+
+	// Attachment indexes
+	attachmentIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "room_id", Value: 1}},
+		},
+	}
+	if _, err := c.db.Collection("attachments").Indexes().CreateMany(ctx, attachmentIndexes); err != nil {
+		return err
+	}
+
+	// Notification indexes
+	notificationIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+	}
+	if _, err := c.db.Collection("notifications").Indexes().CreateMany(ctx, notificationIndexes); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,83 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/meet-clone/backend/internal/core/domain/notification"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(client *Client) notification.Repository {
+	return &NotificationRepository{
+		collection: client.GetCollection("notifications"),
+	}
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, n *notification.Notification) error {
+	_, err := r.collection.InsertOne(ctx, n)
+	return err
+}
+
+func (r *NotificationRepository) List(ctx context.Context, filter notification.ListFilter) ([]*notification.Notification, error) {
+	conditions := bson.A{
+		bson.M{"user_id": filter.UserID},
+	}
+	if filter.UnreadOnly {
+		conditions = append(conditions, bson.M{"read_at": nil})
+	}
+	if filter.After != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$lt": filter.After.CreatedAt}},
+			bson.M{"created_at": filter.After.CreatedAt, "_id": bson.M{"$lt": filter.After.ID}},
+		}})
+	}
+
+	opts := options.Find().
+		SetLimit(int64(filter.Limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []*notification.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": nil})
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id string, readAt time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "user_id": userID},
+		bson.M{"$set": bson.M{"read_at": readAt}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string, readAt time.Time) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": readAt}},
+	)
+	return err
+}
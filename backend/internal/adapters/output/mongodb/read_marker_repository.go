@@ -0,0 +1,47 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/chat"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReadMarkerRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReadMarkerRepository(client *Client) chat.ReadMarkerRepository {
+	return &ReadMarkerRepository{
+		collection: client.GetCollection("chat_read_markers"),
+	}
+}
+
+func (r *ReadMarkerRepository) FindReadMarker(ctx context.Context, roomID, userID string) (*chat.ReadMarker, error) {
+	var marker chat.ReadMarker
+	err := r.collection.FindOne(ctx, bson.M{"room_id": roomID, "user_id": userID}).Decode(&marker)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &marker, nil
+}
+
+func (r *ReadMarkerRepository) SaveReadMarker(ctx context.Context, marker *chat.ReadMarker) error {
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"room_id": marker.RoomID, "user_id": marker.UserID},
+		marker,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *ReadMarkerRepository) DeleteByRoomID(ctx context.Context, roomID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"room_id": roomID})
+	return err
+}
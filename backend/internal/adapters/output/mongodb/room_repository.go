@@ -11,11 +11,13 @@ import (
 
 type RoomRepository struct {
 	collection *mongo.Collection
+	reactions  *mongo.Collection
 }
 
 func NewRoomRepository(client *Client) room.Repository {
 	return &RoomRepository{
 		collection: client.GetCollection("rooms"),
+		reactions:  client.GetCollection("room_reactions"),
 	}
 }
 
@@ -33,13 +35,32 @@ func (r *RoomRepository) FindByID(ctx context.Context, id string) (*room.Room, e
 	return &rm, nil
 }
 
-func (r *RoomRepository) Update(ctx context.Context, room *room.Room) error {
-	_, err := r.collection.UpdateOne(
+func (r *RoomRepository) Update(ctx context.Context, rm *room.Room) error {
+	version := rm.Version
+
+	// Rooms stored before versioning have no version field
+	var versionFilter interface{} = version
+	if version == 0 {
+		versionFilter = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	rm.Version = version + 1
+	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": room.ID},
-		bson.M{"$set": room},
+		bson.M{"_id": rm.ID, "version": versionFilter},
+		bson.M{"$set": rm},
 	)
-	return err
+	if err != nil {
+		rm.Version = version
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		rm.Version = version
+		return room.ErrVersionConflict
+	}
+
+	return nil
 }
 
 func (r *RoomRepository) Delete(ctx context.Context, id string) error {
@@ -87,3 +108,108 @@ func (r *RoomRepository) FindByCreator(ctx context.Context, createdBy string) ([
 
 	return rooms, nil
 }
+
+func (r *RoomRepository) List(ctx context.Context, filter room.ListFilter) ([]*room.Room, error) {
+	conditions := bson.A{}
+
+	if filter.ViewerID != "" {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"created_by": filter.ViewerID},
+			bson.M{"participants.user_id": filter.ViewerID},
+		}})
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, bson.M{"status": filter.Status})
+	}
+	if filter.CreatedBy != "" {
+		conditions = append(conditions, bson.M{"created_by": filter.CreatedBy})
+	}
+	if filter.ParticipantID != "" {
+		conditions = append(conditions, bson.M{"participants.user_id": filter.ParticipantID})
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$gte": filter.CreatedAfter}})
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$lt": filter.CreatedBefore}})
+	}
+	if filter.Query != "" {
+		conditions = append(conditions, bson.M{"$text": bson.M{"$search": filter.Query}})
+	}
+	if filter.ChannelID != "" {
+		conditions = append(conditions, bson.M{"channel_id": filter.ChannelID})
+	}
+	if filter.WithChat {
+		conditions = append(conditions, bson.M{"chat_purged_at": bson.M{"$exists": false}})
+	}
+
+	direction := -1
+	comparison := "$lt"
+	if filter.Sort == room.SortOldest {
+		direction = 1
+		comparison = "$gt"
+	}
+
+	if filter.After != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{comparison: filter.After.CreatedAt}},
+			bson.M{"created_at": filter.After.CreatedAt, "_id": bson.M{comparison: filter.After.ID}},
+		}})
+	}
+
+	query := bson.M{}
+	if len(conditions) > 0 {
+		query["$and"] = conditions
+	}
+
+	opts := options.Find().
+		SetLimit(int64(filter.Limit)).
+		SetSort(bson.D{{Key: "created_at", Value: direction}, {Key: "_id", Value: direction}})
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rooms := []*room.Room{}
+	if err := cursor.All(ctx, &rooms); err != nil {
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+// Reaction counts live in their own collection so that incrementing them
+// does not race with the versioned updates of the room document.
+func (r *RoomRepository) AddReactions(ctx context.Context, roomID string, counts map[string]int) error {
+	inc := bson.M{}
+	for emoji, count := range counts {
+		inc["counts."+emoji] = count
+	}
+	if len(inc) == 0 {
+		return nil
+	}
+
+	_, err := r.reactions.UpdateOne(
+		ctx,
+		bson.M{"_id": roomID},
+		bson.M{"$inc": inc},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *RoomRepository) FindReactions(ctx context.Context, roomID string) (map[string]int, error) {
+	var doc struct {
+		Counts map[string]int `bson:"counts"`
+	}
+	err := r.reactions.FindOne(ctx, bson.M{"_id": roomID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return map[string]int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc.Counts, nil
+}
@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/template"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TemplateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTemplateRepository(client *Client) template.Repository {
+	return &TemplateRepository{
+		collection: client.GetCollection("room_templates"),
+	}
+}
+
+func (r *TemplateRepository) Create(ctx context.Context, t *template.Template) error {
+	_, err := r.collection.InsertOne(ctx, t)
+	return err
+}
+
+func (r *TemplateRepository) FindByID(ctx context.Context, id string) (*template.Template, error) {
+	var t template.Template
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&t)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TemplateRepository) FindVisible(ctx context.Context, ownerID, organizationID string) ([]*template.Template, error) {
+	filter := bson.M{"owner_id": ownerID}
+	if organizationID != "" {
+		filter = bson.M{"$or": bson.A{
+			bson.M{"owner_id": ownerID},
+			bson.M{"organization_id": organizationID},
+		}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"name": 1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	templates := []*template.Template{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (r *TemplateRepository) Update(ctx context.Context, t *template.Template) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": t.ID},
+		bson.M{"$set": t},
+	)
+	return err
+}
+
+func (r *TemplateRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
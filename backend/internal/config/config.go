@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,6 +19,29 @@ type Config struct {
 	CORSOrigin          string
 	CloudflareAppID     string
 	CloudflareAppSecret string
+	ReaperInterval      time.Duration
+	ParticipantGrace    time.Duration
+	RoomIdleTimeout     time.Duration
+	MaxMeetingDuration  time.Duration
+	MeetingWarnings     []time.Duration
+	MaxExtensions       int
+	MaxExtension        time.Duration
+	TimekeeperInterval  time.Duration
+	SpotlightLimit      int
+	AttachmentDir       string
+	AttachmentMaxSize   int64
+	AttachmentTypes     []string
+	AttachmentRetention time.Duration
+	AttachmentCleanup   time.Duration
+	ChatMaxLength       int
+	ChatBlockedWords    []string
+	ChatBlockedMode     string
+	ChatMaxPins         int
+	ChatRetention       time.Duration
+	ChatOrgRetention    map[string]time.Duration
+	ChatPurgeInterval   time.Duration
+	LegalHoldAdmins     []string
+	OrganizationDomains map[string]string
 }
 
 func Load() *Config {
@@ -39,6 +64,29 @@ func Load() *Config {
 		CORSOrigin:          getEnv("CORS_ORIGIN", "http://localhost:3000"),
 		CloudflareAppID:     getEnv("CLOUDFLARE_APP_ID", ""),
 		CloudflareAppSecret: getEnv("CLOUDFLARE_APP_SECRET", ""),
+		ReaperInterval:      getInterval("REAPER_INTERVAL", time.Minute),
+		ParticipantGrace:    getDuration("PARTICIPANT_GRACE_PERIOD", 2*time.Minute),
+		RoomIdleTimeout:     getDuration("ROOM_IDLE_TIMEOUT", 10*time.Minute),
+		MaxMeetingDuration:  getDuration("MAX_MEETING_DURATION", 0),
+		MeetingWarnings:     getDurations("MEETING_WARNINGS", []time.Duration{10 * time.Minute, time.Minute}),
+		MaxExtensions:       getInt("MEETING_MAX_EXTENSIONS", 2),
+		MaxExtension:        getDuration("MEETING_MAX_EXTENSION", 15*time.Minute),
+		TimekeeperInterval:  getInterval("MEETING_TIMER_INTERVAL", 10*time.Second),
+		SpotlightLimit:      getInt("SPOTLIGHT_LIMIT", 3),
+		AttachmentDir:       getEnv("ATTACHMENT_DIR", "./data/attachments"),
+		AttachmentMaxSize:   int64(getInt("ATTACHMENT_MAX_SIZE", 10<<20)),
+		AttachmentTypes:     getList("ATTACHMENT_ALLOWED_TYPES"),
+		AttachmentRetention: getDuration("ATTACHMENT_RETENTION", 30*24*time.Hour),
+		AttachmentCleanup:   getInterval("ATTACHMENT_CLEANUP_INTERVAL", time.Hour),
+		ChatMaxLength:       getInt("CHAT_MAX_MESSAGE_LENGTH", 2000),
+		ChatBlockedWords:    getList("CHAT_BLOCKED_WORDS"),
+		ChatBlockedMode:     getEnv("CHAT_BLOCKED_WORDS_MODE", "mask"),
+		ChatMaxPins:         getInt("CHAT_MAX_PINS", 5),
+		ChatRetention:       getDuration("CHAT_RETENTION", 0),
+		ChatOrgRetention:    getDurationMap("CHAT_ORG_RETENTION"),
+		ChatPurgeInterval:   getInterval("CHAT_PURGE_INTERVAL", time.Hour),
+		LegalHoldAdmins:     getList("LEGAL_HOLD_ADMINS"),
+		OrganizationDomains: getDomainMap("ORGANIZATION_DOMAINS"),
 	}
 }
 
@@ -48,3 +96,99 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, defaultValue.String()))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getInterval reads how often a background job runs. Tickers need a
+// positive interval, so anything else falls back to the default.
+func getInterval(key string, defaultValue time.Duration) time.Duration {
+	value := getDuration(key, defaultValue)
+	if value <= 0 {
+		log.Printf("Ignoring %s=%s: the interval must be positive, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getDurations(key string, defaultValue []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return defaultValue
+		}
+		durations = append(durations, d)
+	}
+	return durations
+}
+
+// getList splits a comma separated value, returning nil when it is unset.
+func getList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	var items []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// getDurationMap parses "key=duration" pairs separated by commas, skipping
+// malformed pairs.
+func getDurationMap(key string) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for _, item := range getList(key) {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			log.Printf("Ignoring %s entry %q: expected name=duration", key, item)
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("Ignoring %s entry %q: %v", key, item, err)
+			continue
+		}
+		durations[strings.TrimSpace(name)] = d
+	}
+	return durations
+}
+
+// getDomainMap parses "domain=value" pairs separated by commas, with the
+// domains in lower case, skipping malformed pairs.
+func getDomainMap(key string) map[string]string {
+	domains := make(map[string]string)
+	for _, item := range getList(key) {
+		domain, value, ok := strings.Cut(item, "=")
+		domain, value = strings.TrimSpace(domain), strings.TrimSpace(value)
+		if !ok || domain == "" || value == "" {
+			log.Printf("Ignoring %s entry %q: expected domain=organization", key, item)
+			continue
+		}
+		domains[strings.ToLower(domain)] = value
+	}
+	return domains
+}
+
+func getInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
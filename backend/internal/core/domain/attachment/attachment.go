@@ -0,0 +1,98 @@
+package attachment
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file uploaded to the chat of a room. The content lives in
+// Storage under the attachment ID.
+type Attachment struct {
+	ID          string    `json:"id" bson:"_id"`
+	RoomID      string    `json:"room_id" bson:"room_id"`
+	UploadedBy  string    `json:"uploaded_by" bson:"uploaded_by"`
+	FileName    string    `json:"file_name" bson:"file_name"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Size        int64     `json:"size" bson:"size"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+}
+
+func NewAttachment(roomID, uploadedBy, fileName string) *Attachment {
+	return &Attachment{
+		ID:         uuid.New().String(),
+		RoomID:     roomID,
+		UploadedBy: uploadedBy,
+		FileName:   cleanFileName(fileName),
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Policy limits what can be uploaded.
+type Policy struct {
+	// MaxSize is the largest accepted file in bytes.
+	MaxSize int64
+	// AllowedTypes are the accepted media types, as detected from the
+	// content rather than declared by the client.
+	AllowedTypes []string
+	// Retention is how long attachments are kept after the meeting ends,
+	// or longer when the chat they were shared in is kept longer. Zero
+	// keeps them forever.
+	Retention time.Duration
+}
+
+// DefaultAllowedTypes are the media types accepted when none are configured.
+var DefaultAllowedTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+}
+
+func (p Policy) allows(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	allowed := p.AllowedTypes
+	if len(allowed) == 0 {
+		allowed = DefaultAllowedTypes
+	}
+	for _, t := range allowed {
+		if strings.EqualFold(t, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxFileNameLength bounds the stored file name.
+const maxFileNameLength = 255
+
+// cleanFileName drops any directories and control characters from a client
+// supplied file name.
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > maxFileNameLength {
+		name = string(runes[:maxFileNameLength])
+	}
+	return name
+}
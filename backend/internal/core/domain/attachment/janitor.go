@@ -0,0 +1,48 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"github.com/meet-clone/backend/internal/pkg/logger"
+)
+
+// Janitor deletes the attachments of ended rooms once the retention period
+// passes.
+type Janitor struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewJanitor(service Service, interval time.Duration) *Janitor {
+	return &Janitor{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Run purges expired attachments every interval until ctx is cancelled.
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.Purge(ctx)
+		}
+	}
+}
+
+// Purge deletes the expired attachments once.
+func (j *Janitor) Purge(ctx context.Context) {
+	purged, err := j.service.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		logger.Error.Printf("Janitor failed to purge attachments: %v", err)
+	}
+	if purged > 0 {
+		logger.Info.Printf("Janitor purged %d expired attachments", purged)
+	}
+}
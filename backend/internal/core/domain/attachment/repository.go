@@ -0,0 +1,27 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, attachment *Attachment) error
+	FindByID(ctx context.Context, id string) (*Attachment, error)
+	// FindCreatedBefore returns up to limit attachments uploaded before the
+	// given time, oldest first, skipping the first offset.
+	FindCreatedBefore(ctx context.Context, before time.Time, limit, offset int) ([]*Attachment, error)
+	// FindByRoomID returns up to limit attachments of the room.
+	FindByRoomID(ctx context.Context, roomID string, limit int) ([]*Attachment, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// Storage holds the content of attachments.
+type Storage interface {
+	// Put stores the content under key, replacing any previous content.
+	Put(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
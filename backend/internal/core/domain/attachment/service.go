@@ -0,0 +1,287 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+type Service interface {
+	// Upload stores a file sent by a participant of an active room.
+	Upload(ctx context.Context, roomID, userID, fileName string, content io.Reader) (*Attachment, error)
+	// GetAttachment returns the metadata of an attachment of the room,
+	// provided the user is a member of the room.
+	GetAttachment(ctx context.Context, roomID, id, userID string) (*Attachment, error)
+	// Open returns an attachment together with its content. The caller
+	// closes the content.
+	Open(ctx context.Context, roomID, id, userID string) (*Attachment, io.ReadCloser, error)
+	// PurgeExpired deletes the attachments of rooms that ended longer than
+	// the retention period ago and returns how many were deleted. Rooms
+	// on legal hold keep their attachments, and rooms whose chat is kept
+	// longer keep them as long as the chat.
+	PurgeExpired(ctx context.Context, now time.Time) (int, error)
+	// DeleteRoomAttachments deletes every attachment of the room and
+	// returns how many were deleted.
+	DeleteRoomAttachments(ctx context.Context, roomID string) (int, error)
+}
+
+// Rooms gives attachments access to the rooms they are uploaded to.
+type Rooms interface {
+	GetRoomDetails(ctx context.Context, roomID string) (*room.Room, error)
+}
+
+// ChatRetention tells how long the chat of a room is kept after the
+// meeting ends, zero meaning forever. Attachments are shared in the chat,
+// so they are never deleted before it.
+type ChatRetention interface {
+	ChatRetention(ctx context.Context, rm *room.Room) (time.Duration, error)
+}
+
+type service struct {
+	repo          Repository
+	storage       Storage
+	rooms         Rooms
+	chatRetention ChatRetention
+	policy        Policy
+}
+
+func NewService(repo Repository, storage Storage, rooms Rooms, chatRetention ChatRetention, policy Policy) Service {
+	return &service{
+		repo:          repo,
+		storage:       storage,
+		rooms:         rooms,
+		chatRetention: chatRetention,
+		policy:        policy,
+	}
+}
+
+// sniffLength is how much content http.DetectContentType looks at.
+const sniffLength = 512
+
+func (s *service) Upload(ctx context.Context, roomID, userID, fileName string, content io.Reader) (*Attachment, error) {
+	rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !rm.IsActive() {
+		return nil, errors.NewValidationError("room has ended")
+	}
+	if !rm.IsParticipant(userID) {
+		return nil, errors.NewForbiddenError("only participants can upload attachments")
+	}
+	if rm.Settings.ChatDisabled {
+		return nil, errors.NewForbiddenError("chat is disabled in this room")
+	}
+
+	// The type is detected from the content, so a renamed executable is
+	// not accepted as an image
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return nil, errors.NewValidationError("file is empty")
+		}
+		return nil, errors.NewValidationError("failed to read file")
+	}
+	head = head[:n]
+
+	a := NewAttachment(roomID, userID, fileName)
+	a.ContentType = http.DetectContentType(head)
+	if !s.policy.allows(a.ContentType) {
+		return nil, errors.NewValidationError("file type " + a.ContentType + " is not allowed")
+	}
+
+	body := &limitedReader{
+		r:   io.MultiReader(bytes.NewReader(head), content),
+		max: s.policy.MaxSize,
+	}
+	if err := s.storage.Put(ctx, a.ID, body); err != nil {
+		s.discard(ctx, a.ID)
+		if body.exceeded {
+			return nil, errors.NewValidationError("file is larger than " + strconv.FormatInt(s.policy.MaxSize, 10) + " bytes")
+		}
+		return nil, errors.NewInternalError("failed to store attachment", err)
+	}
+	a.Size = body.read
+
+	if err := s.repo.Create(ctx, a); err != nil {
+		s.discard(ctx, a.ID)
+		return nil, errors.NewInternalError("failed to save attachment", err)
+	}
+
+	return a, nil
+}
+
+func (s *service) GetAttachment(ctx context.Context, roomID, id, userID string) (*Attachment, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil || a.RoomID != roomID {
+		return nil, errors.NewNotFoundError("attachment not found")
+	}
+
+	rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !rm.IsMember(userID) {
+		return nil, errors.NewForbiddenError("only members of the room can access its attachments")
+	}
+
+	return a, nil
+}
+
+func (s *service) Open(ctx context.Context, roomID, id, userID string) (*Attachment, io.ReadCloser, error) {
+	a, err := s.GetAttachment(ctx, roomID, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Open(ctx, a.ID)
+	if err != nil {
+		return nil, nil, errors.NewInternalError("failed to open attachment", err)
+	}
+
+	return a, content, nil
+}
+
+// purgeBatchSize is how many expired attachments are loaded at a time.
+const purgeBatchSize = 100
+
+func (s *service) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	if s.policy.Retention <= 0 {
+		return 0, nil
+	}
+
+	// Rooms end after their uploads, so only attachments uploaded before
+	// the cutoff can belong to a room that ended before it. Kept ones stay
+	// at the front of the remaining attachments and are skipped.
+	cutoff := now.Add(-s.policy.Retention)
+	rooms := make(map[string]bool)
+	purged, kept := 0, 0
+	for {
+		candidates, err := s.repo.FindCreatedBefore(ctx, cutoff, purgeBatchSize, kept)
+		if err != nil {
+			return purged, errors.NewInternalError("failed to find expired attachments", err)
+		}
+
+		for _, a := range candidates {
+			expired, err := s.roomExpired(ctx, rooms, a.RoomID, now)
+			if err != nil {
+				return purged, err
+			}
+			if !expired {
+				kept++
+				continue
+			}
+			if err := s.delete(ctx, a); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(candidates) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// roomExpired reports whether the attachments of the room expired by now:
+// the room ended longer than the retention period ago, or the retention
+// of its chat if that is longer, and is not on legal hold. Attachments of
+// rooms that no longer exist have expired too. Results are cached in
+// cache for one purge.
+func (s *service) roomExpired(ctx context.Context, cache map[string]bool, roomID string, now time.Time) (bool, error) {
+	if expired, ok := cache[roomID]; ok {
+		return expired, nil
+	}
+
+	rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if !ok || appErr.Type != errors.ErrorTypeNotFound {
+			return false, err
+		}
+		cache[roomID] = true
+		return true, nil
+	}
+
+	expired := false
+	if !rm.IsActive() && !rm.LegalHold {
+		chatRetention, err := s.chatRetention.ChatRetention(ctx, rm)
+		if err != nil {
+			return false, err
+		}
+		// Chat kept forever keeps its attachments forever
+		retention := s.policy.Retention
+		if chatRetention > retention {
+			retention = chatRetention
+		}
+		expired = chatRetention > 0 && !now.Before(rm.EndedAt.Add(retention))
+	}
+
+	cache[roomID] = expired
+	return expired, nil
+}
+
+func (s *service) DeleteRoomAttachments(ctx context.Context, roomID string) (int, error) {
+	deleted := 0
+	for {
+		batch, err := s.repo.FindByRoomID(ctx, roomID, purgeBatchSize)
+		if err != nil {
+			return deleted, errors.NewInternalError("failed to find attachments", err)
+		}
+
+		for _, a := range batch {
+			if err := s.delete(ctx, a); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+
+		if len(batch) < purgeBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// delete removes the content of an attachment, then its metadata.
+func (s *service) delete(ctx context.Context, a *Attachment) error {
+	if err := s.storage.Delete(ctx, a.ID); err != nil {
+		return errors.NewInternalError("failed to delete attachment content", err)
+	}
+	if err := s.repo.Delete(ctx, a.ID); err != nil {
+		return errors.NewInternalError("failed to delete attachment", err)
+	}
+	return nil
+}
+
+// discard removes content stored for an upload that failed.
+func (s *service) discard(ctx context.Context, key string) {
+	_ = s.storage.Delete(ctx, key)
+}
+
+// limitedReader fails once more than max bytes were read. A max of zero or
+// less does not limit.
+type limitedReader struct {
+	r        io.Reader
+	max      int64
+	read     int64
+	exceeded bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.max > 0 && l.read > l.max {
+		l.exceeded = true
+		return n, errFileTooLarge
+	}
+	return n, err
+}
+
+var errFileTooLarge = errors.NewValidationError("file is too large")
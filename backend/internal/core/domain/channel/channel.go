@@ -0,0 +1,110 @@
+package channel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+type MemberRole string
+
+const (
+	RoleAdmin  MemberRole = "admin"
+	RoleMember MemberRole = "member"
+)
+
+// Channel is a persistent conversation of a team or a recurring meeting.
+// Its chat continues between calls, and the calls started from it are
+// linked to it.
+type Channel struct {
+	ID          string `json:"id" bson:"_id"`
+	Name        string `json:"name" bson:"name"`
+	Description string `json:"description" bson:"description"`
+	CreatedBy   string `json:"created_by" bson:"created_by"`
+	// OrganizationID lets every member of the organization join the
+	// channel. Empty keeps it invite only.
+	OrganizationID string `json:"organization_id,omitempty" bson:"organization_id,omitempty"`
+	// TemplateID is the room template calls of the channel start from,
+	// for channels of a recurring meeting.
+	TemplateID string    `json:"template_id,omitempty" bson:"template_id,omitempty"`
+	Members    []Member  `json:"members" bson:"members"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
+
+type Member struct {
+	UserID   string     `json:"user_id" bson:"user_id"`
+	Name     string     `json:"name" bson:"name"`
+	Avatar   string     `json:"avatar" bson:"avatar"`
+	Role     MemberRole `json:"role" bson:"role"`
+	JoinedAt time.Time  `json:"joined_at" bson:"joined_at"`
+}
+
+func NewChannel(createdBy, name string) *Channel {
+	return &Channel{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedBy: createdBy,
+		Members:   []Member{},
+		CreatedAt: time.Now(),
+	}
+}
+
+func NewMember(userID, name, avatar string, role MemberRole) Member {
+	return Member{
+		UserID:   userID,
+		Name:     name,
+		Avatar:   avatar,
+		Role:     role,
+		JoinedAt: time.Now(),
+	}
+}
+
+func (c *Channel) IsMember(userID string) bool {
+	_, ok := c.member(userID)
+	return ok
+}
+
+// IsAdmin reports whether the user manages the channel. The creator is
+// always an admin.
+func (c *Channel) IsAdmin(userID string) bool {
+	if c.CreatedBy == userID {
+		return true
+	}
+	m, ok := c.member(userID)
+	return ok && m.Role == RoleAdmin
+}
+
+func (c *Channel) member(userID string) (Member, bool) {
+	for _, m := range c.Members {
+		if m.UserID == userID {
+			return m, true
+		}
+	}
+	return Member{}, false
+}
+
+// Room presents the channel as an always active room whose participants
+// are the members and whose hosts are the admins, so chat works the same
+// in channels as in meetings.
+func (c *Channel) Room() *room.Room {
+	rm := room.NewRoom(c.CreatedBy, len(c.Members))
+	rm.ID = c.ID
+	rm.Title = c.Name
+	rm.CreatedAt = c.CreatedAt
+
+	for _, m := range c.Members {
+		role := room.RoleParticipant
+		if c.IsAdmin(m.UserID) {
+			role = room.RoleHost
+		}
+		rm.Participants = append(rm.Participants, room.Participant{
+			UserID:   m.UserID,
+			Name:     m.Name,
+			Avatar:   m.Avatar,
+			Role:     role,
+			JoinedAt: m.JoinedAt,
+		})
+	}
+	return rm
+}
@@ -0,0 +1,48 @@
+package channel
+
+import (
+	"context"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+// RoomLookup is the part of the room service conversations build on.
+type RoomLookup interface {
+	GetRoomDetails(ctx context.Context, roomID string) (*room.Room, error)
+	ListRooms(ctx context.Context, filter room.ListFilter) (*room.RoomPage, error)
+}
+
+// Conversations looks up rooms and channels alike, presenting channels as
+// rooms, so the chat service, commands and attachments serve channels
+// without an active call.
+type Conversations struct {
+	rooms RoomLookup
+	repo  Repository
+}
+
+func NewConversations(rooms RoomLookup, repo Repository) *Conversations {
+	return &Conversations{
+		rooms: rooms,
+		repo:  repo,
+	}
+}
+
+// GetRoomDetails returns the room, or the channel with the ID as a room.
+func (c *Conversations) GetRoomDetails(ctx context.Context, id string) (*room.Room, error) {
+	rm, err := c.rooms.GetRoomDetails(ctx, id)
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Type != errors.ErrorTypeNotFound {
+		return rm, err
+	}
+
+	ch, chErr := c.repo.FindByID(ctx, id)
+	if chErr != nil {
+		return nil, err
+	}
+	return ch.Room(), nil
+}
+
+// ListRooms lists meeting rooms only.
+func (c *Conversations) ListRooms(ctx context.Context, filter room.ListFilter) (*room.RoomPage, error) {
+	return c.rooms.ListRooms(ctx, filter)
+}
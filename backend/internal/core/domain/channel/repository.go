@@ -0,0 +1,14 @@
+package channel
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, channel *Channel) error
+	FindByID(ctx context.Context, id string) (*Channel, error)
+	// FindVisible returns the channels the user is a member of or can join
+	// through the organization, sorted by name.
+	FindVisible(ctx context.Context, userID, organizationID string) ([]*Channel, error)
+	// AddMember adds the member unless the user already is one.
+	AddMember(ctx context.Context, channelID string, member Member) error
+	RemoveMember(ctx context.Context, channelID, userID string) error
+}
@@ -0,0 +1,281 @@
+package channel
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/core/domain/user"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+// maxNameLength bounds the name of a channel.
+const maxNameLength = 80
+
+// CreateOptions are the creator's choices for a new channel.
+type CreateOptions struct {
+	Name        string
+	Description string
+	// Shared lets the members of the creator's organization join.
+	Shared bool
+	// TemplateID starts the channel's calls from a room template.
+	TemplateID string
+}
+
+// CallOptions are the choices for a call started from a channel. Set
+// fields override the channel's template.
+type CallOptions struct {
+	Title       string
+	MaxCapacity int
+	MaxDuration time.Duration
+	Settings    room.SettingsUpdate
+}
+
+// Users looks up the name, avatar and organization of members.
+type Users interface {
+	GetByID(ctx context.Context, id string) (*user.User, error)
+}
+
+// Rooms starts and lists the calls of channels.
+type Rooms interface {
+	CreateRoom(ctx context.Context, userID string, opts room.CreateOptions) (*room.Room, error)
+	ListRooms(ctx context.Context, filter room.ListFilter) (*room.RoomPage, error)
+}
+
+// Templates resolves the room template of a recurring meeting's channel.
+type Templates interface {
+	RoomOptions(ctx context.Context, id, userID string) (room.CreateOptions, error)
+}
+
+type Service interface {
+	CreateChannel(ctx context.Context, userID string, opts CreateOptions) (*Channel, error)
+	// GetChannel returns a channel the user is a member of.
+	GetChannel(ctx context.Context, channelID, userID string) (*Channel, error)
+	// ListChannels returns the user's channels and the ones shared with
+	// their organization.
+	ListChannels(ctx context.Context, userID string) ([]*Channel, error)
+	// JoinChannel adds the user to a channel shared with their
+	// organization.
+	JoinChannel(ctx context.Context, channelID, userID string) (*Channel, error)
+	// AddMember adds a user to the channel. Only admins can add members.
+	AddMember(ctx context.Context, channelID, userID, targetID string) (*Channel, error)
+	// RemoveMember removes a member. Members may leave, and admins may
+	// remove anyone but the creator.
+	RemoveMember(ctx context.Context, channelID, userID, targetID string) (*Channel, error)
+	// StartCall creates a room linked to the channel. Only members can
+	// start calls.
+	StartCall(ctx context.Context, channelID, userID string, opts CallOptions) (*room.Room, error)
+	// ListCalls returns the calls started from the channel, newest first.
+	ListCalls(ctx context.Context, channelID, userID string, filter room.ListFilter) (*room.RoomPage, error)
+}
+
+type service struct {
+	repo      Repository
+	users     Users
+	rooms     Rooms
+	templates Templates
+}
+
+func NewService(repo Repository, users Users, rooms Rooms, templates Templates) Service {
+	return &service{
+		repo:      repo,
+		users:     users,
+		rooms:     rooms,
+		templates: templates,
+	}
+}
+
+func (s *service) CreateChannel(ctx context.Context, userID string, opts CreateOptions) (*Channel, error) {
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		return nil, errors.NewValidationError("name is required")
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return nil, errors.NewValidationError("name must be at most 80 characters")
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := NewChannel(userID, name)
+	ch.Description = strings.TrimSpace(opts.Description)
+	if opts.Shared {
+		if u.OrganizationID == "" {
+			return nil, errors.NewValidationError("only members of an organization can share channels")
+		}
+		ch.OrganizationID = u.OrganizationID
+	}
+	if opts.TemplateID != "" {
+		// Fails unless the creator can see the template
+		if _, err := s.templates.RoomOptions(ctx, opts.TemplateID, userID); err != nil {
+			return nil, err
+		}
+		ch.TemplateID = opts.TemplateID
+	}
+	ch.Members = append(ch.Members, NewMember(u.ID, u.Name, u.Avatar, RoleAdmin))
+
+	if err := s.repo.Create(ctx, ch); err != nil {
+		return nil, errors.NewInternalError("failed to create channel", err)
+	}
+
+	return ch, nil
+}
+
+func (s *service) GetChannel(ctx context.Context, channelID, userID string) (*Channel, error) {
+	ch, err := s.repo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("channel not found")
+	}
+
+	// Channels of others are not revealed
+	if !ch.IsMember(userID) {
+		return nil, errors.NewNotFoundError("channel not found")
+	}
+
+	return ch, nil
+}
+
+func (s *service) ListChannels(ctx context.Context, userID string) ([]*Channel, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := s.repo.FindVisible(ctx, userID, u.OrganizationID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to list channels", err)
+	}
+
+	return channels, nil
+}
+
+func (s *service) JoinChannel(ctx context.Context, channelID, userID string) (*Channel, error) {
+	ch, err := s.repo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("channel not found")
+	}
+	if ch.IsMember(userID) {
+		return ch, nil
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if ch.OrganizationID == "" || ch.OrganizationID != u.OrganizationID {
+		return nil, errors.NewNotFoundError("channel not found")
+	}
+
+	return s.addMember(ctx, ch, u)
+}
+
+func (s *service) AddMember(ctx context.Context, channelID, userID, targetID string) (*Channel, error) {
+	ch, err := s.GetChannel(ctx, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ch.IsAdmin(userID) {
+		return nil, errors.NewForbiddenError("only channel admins can add members")
+	}
+	if ch.IsMember(targetID) {
+		return nil, errors.NewAlreadyExistsError("user is already a member of the channel")
+	}
+
+	u, err := s.users.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.addMember(ctx, ch, u)
+}
+
+func (s *service) addMember(ctx context.Context, ch *Channel, u *user.User) (*Channel, error) {
+	member := NewMember(u.ID, u.Name, u.Avatar, RoleMember)
+	if err := s.repo.AddMember(ctx, ch.ID, member); err != nil {
+		return nil, errors.NewInternalError("failed to add member", err)
+	}
+
+	ch.Members = append(ch.Members, member)
+	return ch, nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, channelID, userID, targetID string) (*Channel, error) {
+	ch, err := s.GetChannel(ctx, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != targetID && !ch.IsAdmin(userID) {
+		return nil, errors.NewForbiddenError("only channel admins can remove members")
+	}
+	if targetID == ch.CreatedBy {
+		return nil, errors.NewValidationError("the channel creator cannot be removed")
+	}
+	if !ch.IsMember(targetID) {
+		return nil, errors.NewNotFoundError("member not found")
+	}
+
+	if err := s.repo.RemoveMember(ctx, ch.ID, targetID); err != nil {
+		return nil, errors.NewInternalError("failed to remove member", err)
+	}
+
+	for i, m := range ch.Members {
+		if m.UserID == targetID {
+			ch.Members = append(ch.Members[:i], ch.Members[i+1:]...)
+			break
+		}
+	}
+	return ch, nil
+}
+
+func (s *service) StartCall(ctx context.Context, channelID, userID string, opts CallOptions) (*room.Room, error) {
+	ch, err := s.GetChannel(ctx, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The template was checked against the channel creator's access, so
+	// it is resolved as them whichever member starts the call
+	var base room.CreateOptions
+	if ch.TemplateID != "" {
+		if base, err = s.templates.RoomOptions(ctx, ch.TemplateID, ch.CreatedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	create := mergeOptions(base, opts)
+	if create.Title == "" {
+		create.Title = ch.Name
+	}
+	create.ChannelID = ch.ID
+
+	return s.rooms.CreateRoom(ctx, userID, create)
+}
+
+// mergeOptions applies the options set for a call over the template.
+func mergeOptions(base room.CreateOptions, opts CallOptions) room.CreateOptions {
+	if opts.Title != "" {
+		base.Title = opts.Title
+	}
+	if opts.MaxCapacity != 0 {
+		base.MaxCapacity = opts.MaxCapacity
+	}
+	if opts.MaxDuration != 0 {
+		base.MaxDuration = opts.MaxDuration
+	}
+	base.Settings = opts.Settings.Apply(base.Settings)
+	return base
+}
+
+func (s *service) ListCalls(ctx context.Context, channelID, userID string, filter room.ListFilter) (*room.RoomPage, error) {
+	if _, err := s.GetChannel(ctx, channelID, userID); err != nil {
+		return nil, err
+	}
+
+	filter.ChannelID = channelID
+	filter.ViewerID = ""
+	return s.rooms.ListRooms(ctx, filter)
+}
@@ -0,0 +1,85 @@
+package chat
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// MessageQuery selects a page of room messages. Without a cursor the page
+// holds the newest messages.
+type MessageQuery struct {
+	RoomID   string
+	ViewerID string
+	// Before pages back to the messages older than the cursor.
+	Before *Cursor
+	// After pages forward to the messages newer than the cursor.
+	After *Cursor
+	Limit int
+	// Offset skips the newest messages instead of using a cursor. Nil
+	// pages with the cursors; any offset, zero included, uses offset mode.
+	//
+	// Deprecated: offsets return duplicates and gaps while messages
+	// arrive, and get slow in long meetings. Use Before and After.
+	Offset *int
+}
+
+// MessagePage is a page of messages in chronological order.
+type MessagePage struct {
+	Messages []*Message `json:"messages"`
+	// PrevCursor fetches the older messages, if there are any.
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	// NextCursor fetches the newer messages, if there are any.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Cursor identifies a position in a list of messages. Messages are ordered
+// by timestamp, with the ID breaking ties.
+type Cursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+func cursorOf(m *Message) Cursor {
+	return Cursor{Timestamp: m.Timestamp, ID: m.ID}
+}
+
+// after reports whether the cursor comes after other.
+func (c Cursor) after(other Cursor) bool {
+	if !c.Timestamp.Equal(other.Timestamp) {
+		return c.Timestamp.After(other.Timestamp)
+	}
+	return c.ID > other.ID
+}
+
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.Timestamp.UnixNano(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid cursor")
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, errors.NewValidationError("invalid cursor")
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid cursor")
+	}
+
+	return &Cursor{Timestamp: time.Unix(0, n).UTC(), ID: id}, nil
+}
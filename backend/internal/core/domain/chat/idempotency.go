@@ -0,0 +1,22 @@
+package chat
+
+import "errors"
+
+// maxClientMsgIDLength bounds the client-generated ID of a message.
+const maxClientMsgIDLength = 64
+
+// ErrDuplicateClientMsgID is returned by Repository.Create when the sender
+// already stored a message with the same client message ID in the room.
+var ErrDuplicateClientMsgID = errors.New("duplicate client message ID")
+
+// Duplicate is returned by SendMessage when the sender already sent a
+// message with the same client message ID, for example when a client
+// resends after reconnecting. Nothing is stored again.
+type Duplicate struct {
+	// Message is the message stored the first time.
+	Message *Message
+}
+
+func (d *Duplicate) Error() string {
+	return "message " + d.Message.ClientMsgID + " was already sent"
+}
@@ -0,0 +1,59 @@
+package chat
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+// Mention is a participant mentioned with @name in a message.
+type Mention struct {
+	UserID string `json:"user_id" bson:"user_id"`
+	Name   string `json:"name" bson:"name"`
+}
+
+// parseMentions finds the @name mentions of the participants in the text.
+// Names may contain spaces, so the longest matching name wins: with "Ann"
+// and "Ann Lee" in the room, "@Ann Lee" mentions Ann Lee.
+func parseMentions(text string, participants []room.Participant) []Mention {
+	candidates := make([]room.Participant, 0, len(participants))
+	for _, p := range participants {
+		if strings.TrimSpace(p.Name) != "" {
+			candidates = append(candidates, p)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return len(candidates[i].Name) > len(candidates[j].Name)
+	})
+
+	var mentions []Mention
+	seen := make(map[string]bool)
+	for i := 0; i < len(text); i++ {
+		if text[i] != '@' {
+			continue
+		}
+		// An @ inside a word, as in an email address, is not a mention
+		if before, _ := utf8.DecodeLastRuneInString(text[:i]); i > 0 && isWordRune(before) {
+			continue
+		}
+
+		rest := text[i+1:]
+		for _, p := range candidates {
+			if len(rest) < len(p.Name) || !strings.EqualFold(rest[:len(p.Name)], p.Name) {
+				continue
+			}
+			if after, _ := utf8.DecodeRuneInString(rest[len(p.Name):]); len(rest) > len(p.Name) && isWordRune(after) {
+				continue
+			}
+			if !seen[p.UserID] {
+				seen[p.UserID] = true
+				mentions = append(mentions, Mention{UserID: p.UserID, Name: p.Name})
+			}
+			i += len(p.Name)
+			break
+		}
+	}
+	return mentions
+}
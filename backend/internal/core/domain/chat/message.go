@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/meet-clone/backend/internal/core/domain/attachment"
 )
 
 type Message struct {
@@ -13,6 +14,74 @@ type Message struct {
 	UserName  string    `json:"user_name" bson:"user_name"`
 	Message   string    `json:"message" bson:"message"`
 	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	EditedAt  time.Time `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	// History holds the earlier versions of an edited message, oldest first.
+	History   []Revision `json:"history,omitempty" bson:"history,omitempty"`
+	Deleted   bool       `json:"deleted" bson:"deleted"`
+	DeletedAt time.Time  `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+	// ParentID is the first message of the thread this message replies to.
+	ParentID   string `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	ReplyCount int    `json:"reply_count" bson:"reply_count"`
+	Quote      *Quote `json:"quote,omitempty" bson:"quote,omitempty"`
+	// RecipientID makes the message private between its author and the
+	// recipient. Empty means the whole room can see it.
+	RecipientID string `json:"recipient_id,omitempty" bson:"recipient_id,omitempty"`
+	// Attachment describes a file shared with the message. The content is
+	// downloaded separately and may have expired.
+	Attachment *attachment.Attachment `json:"attachment,omitempty" bson:"attachment,omitempty"`
+	// Mentions are the participants mentioned with @name when the message
+	// was sent.
+	Mentions []Mention `json:"mentions,omitempty" bson:"mentions,omitempty"`
+	// Pinned messages are shown at the top of the chat. Deleting a message
+	// unpins it.
+	Pinned   bool      `json:"pinned,omitempty" bson:"pinned,omitempty"`
+	PinnedAt time.Time `json:"pinned_at,omitempty" bson:"pinned_at,omitempty"`
+	PinnedBy string    `json:"pinned_by,omitempty" bson:"pinned_by,omitempty"`
+	// ClientMsgID is the ID the sender's client gave the message, unique
+	// per sender and room, so a resent message is stored only once.
+	ClientMsgID string `json:"client_msg_id,omitempty" bson:"client_msg_id,omitempty"`
+}
+
+// Quote is a copy of the message being replied to, as it read at the time.
+type Quote struct {
+	MessageID string `json:"message_id" bson:"message_id"`
+	UserName  string `json:"user_name" bson:"user_name"`
+	Message   string `json:"message" bson:"message"`
+}
+
+// maxQuoteLength bounds the quoted text stored with a reply.
+const maxQuoteLength = 280
+
+// Draft is a message a participant wants to send.
+type Draft struct {
+	RoomID   string
+	UserID   string
+	UserName string
+	Message  string
+	// ParentID makes the message a reply in the thread of that message.
+	ParentID string
+	// Quote includes the text of the parent message in the reply.
+	Quote bool
+	// RecipientID sends the message privately to that participant.
+	RecipientID string
+	// AttachmentID shares a file the sender uploaded to the room.
+	AttachmentID string
+	// ClientMsgID makes sending idempotent. Optional.
+	ClientMsgID string
+}
+
+// Thread is a message together with its replies.
+type Thread struct {
+	Parent  *Message   `json:"parent"`
+	Replies []*Message `json:"replies"`
+}
+
+// Revision is an earlier version of an edited message.
+type Revision struct {
+	Message string `json:"message" bson:"message"`
+	// ReplacedAt is when this version was replaced by an edit.
+	ReplacedAt time.Time `json:"replaced_at" bson:"replaced_at"`
 }
 
 func NewMessage(roomID, userID, userName, message string) *Message {
@@ -25,3 +94,39 @@ func NewMessage(roomID, userID, userName, message string) *Message {
 		Timestamp: time.Now(),
 	}
 }
+
+// quote copies the message for quoting in a reply.
+func (m *Message) quote() *Quote {
+	text := []rune(m.Message)
+	if len(text) > maxQuoteLength {
+		text = append(text[:maxQuoteLength], '…')
+	}
+	return &Quote{
+		MessageID: m.ID,
+		UserName:  m.UserName,
+		Message:   string(text),
+	}
+}
+
+// IsPrivate reports whether the message is a direct message.
+func (m *Message) IsPrivate() bool {
+	return m.RecipientID != ""
+}
+
+// VisibleTo reports whether the user may read the message.
+func (m *Message) VisibleTo(userID string) bool {
+	return !m.IsPrivate() || m.UserID == userID || m.RecipientID == userID
+}
+
+// Redact hides the content of a deleted message, leaving a tombstone that
+// keeps its place in the conversation.
+func (m *Message) Redact() {
+	if !m.Deleted {
+		return
+	}
+	m.Message = ""
+	m.History = nil
+	m.Quote = nil
+	m.Attachment = nil
+	m.Mentions = nil
+}
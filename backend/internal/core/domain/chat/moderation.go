@@ -0,0 +1,199 @@
+package chat
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+// Submission is a message on its way to being saved.
+type Submission struct {
+	Room    *room.Room
+	Message *Message
+	// Edit is set when the text of an existing message is being replaced.
+	Edit bool
+}
+
+// Moderator checks a submission before it is saved. It may change the
+// message text, and rejects the message by returning a *Rejection.
+type Moderator interface {
+	Moderate(ctx context.Context, sub *Submission) error
+}
+
+// ModeratorFunc adapts a function to a Moderator.
+type ModeratorFunc func(ctx context.Context, sub *Submission) error
+
+func (f ModeratorFunc) Moderate(ctx context.Context, sub *Submission) error {
+	return f(ctx, sub)
+}
+
+// RejectionCode tells the sender why a message was rejected.
+type RejectionCode string
+
+const (
+	RejectTooLong     RejectionCode = "message_too_long"
+	RejectBlockedWord RejectionCode = "blocked_word"
+	RejectSlowMode    RejectionCode = "slow_mode"
+	RejectChatMuted   RejectionCode = "chat_muted"
+)
+
+// Rejection is returned when moderation refuses a message.
+type Rejection struct {
+	Code    RejectionCode
+	Message string
+	// RetryAfter is how long to wait before sending again, if waiting
+	// helps.
+	RetryAfter time.Duration
+}
+
+func (r *Rejection) Error() string {
+	return string(r.Code) + ": " + r.Message
+}
+
+// ChatMute rejects messages from participants whose chat a host muted.
+func ChatMute() Moderator {
+	return ModeratorFunc(func(ctx context.Context, sub *Submission) error {
+		if sub.Room.IsChatMuted(sub.Message.UserID) {
+			return &Rejection{Code: RejectChatMuted, Message: "a host has muted your chat"}
+		}
+		return nil
+	})
+}
+
+// MaxLength rejects messages longer than max characters.
+func MaxLength(max int) Moderator {
+	return ModeratorFunc(func(ctx context.Context, sub *Submission) error {
+		if utf8.RuneCountInString(sub.Message.Message) > max {
+			return &Rejection{
+				Code:    RejectTooLong,
+				Message: "message is longer than " + strconv.Itoa(max) + " characters",
+			}
+		}
+		return nil
+	})
+}
+
+// WordFilterMode is what happens to a message containing a blocked word.
+type WordFilterMode string
+
+const (
+	// WordFilterMask replaces the blocked words with asterisks.
+	WordFilterMask WordFilterMode = "mask"
+	// WordFilterReject refuses the message.
+	WordFilterReject WordFilterMode = "reject"
+)
+
+// WordFilter masks or rejects messages containing any of the words, which
+// are matched as whole words regardless of case.
+func WordFilter(words []string, mode WordFilterMode) Moderator {
+	var quoted []string
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	// Longer words first, so "badword" is not cut short by "bad"
+	sort.Slice(quoted, func(i, j int) bool {
+		return len(quoted[i]) > len(quoted[j])
+	})
+	if len(quoted) == 0 {
+		return ModeratorFunc(func(ctx context.Context, sub *Submission) error {
+			return nil
+		})
+	}
+
+	pattern := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+
+	return ModeratorFunc(func(ctx context.Context, sub *Submission) error {
+		matches := wholeWords(sub.Message.Message, pattern)
+		if len(matches) == 0 {
+			return nil
+		}
+		if mode == WordFilterReject {
+			return &Rejection{Code: RejectBlockedWord, Message: "message contains a blocked word"}
+		}
+
+		text := sub.Message.Message
+		var masked strings.Builder
+		last := 0
+		for _, m := range matches {
+			masked.WriteString(text[last:m[0]])
+			masked.WriteString(strings.Repeat("*", utf8.RuneCountInString(text[m[0]:m[1]])))
+			last = m[1]
+		}
+		masked.WriteString(text[last:])
+		sub.Message.Message = masked.String()
+		return nil
+	})
+}
+
+// wholeWords returns the matches of the pattern that are not part of a
+// longer word.
+func wholeWords(text string, pattern *regexp.Regexp) [][]int {
+	var words [][]int
+	for _, m := range pattern.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:m[0]])
+		after, _ := utf8.DecodeRuneInString(text[m[1]:])
+		if (m[0] > 0 && isWordRune(before)) || (m[1] < len(text) && isWordRune(after)) {
+			continue
+		}
+		words = append(words, m)
+	}
+	return words
+}
+
+// SlowMode enforces the slow mode setting of a room, which limits
+// participants other than hosts to one message per interval. Edits are not
+// limited.
+func SlowMode() Moderator {
+	s := &slowMode{lastSent: make(map[string]time.Time)}
+	return ModeratorFunc(s.moderate)
+}
+
+// slowModePruneSize is how many senders are remembered before those past
+// any slow mode interval are forgotten.
+const slowModePruneSize = 10000
+
+type slowMode struct {
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+func (s *slowMode) moderate(ctx context.Context, sub *Submission) error {
+	interval := time.Duration(sub.Room.Settings.SlowModeSeconds) * time.Second
+	if sub.Edit || interval <= 0 || sub.Room.IsHost(sub.Message.UserID) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	key := sub.Room.ID + "/" + sub.Message.UserID
+	if last, ok := s.lastSent[key]; ok {
+		if wait := last.Add(interval).Sub(now); wait > 0 {
+			return &Rejection{
+				Code:       RejectSlowMode,
+				Message:    "slow mode is on, wait before sending another message",
+				RetryAfter: wait,
+			}
+		}
+	}
+
+	if len(s.lastSent) >= slowModePruneSize {
+		for k, t := range s.lastSent {
+			if now.Sub(t) > time.Hour {
+				delete(s.lastSent, k)
+			}
+		}
+	}
+	s.lastSent[key] = now
+	return nil
+}
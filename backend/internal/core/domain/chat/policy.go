@@ -0,0 +1,24 @@
+package chat
+
+import "errors"
+
+// ErrPinLimit is returned by Repository.Pin when the room already has the
+// maximum number of pinned messages.
+var ErrPinLimit = errors.New("pin limit reached")
+
+// ErrNotPinnable is returned by Repository.Pin when the message was pinned
+// or deleted in the meantime.
+var ErrNotPinnable = errors.New("message cannot be pinned")
+
+// Policy holds the server-wide limits applied to chat.
+type Policy struct {
+	// MaxPins is how many messages can be pinned in a room at once.
+	MaxPins int
+}
+
+// DefaultPolicy allows five pinned messages per room.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxPins: 5,
+	}
+}
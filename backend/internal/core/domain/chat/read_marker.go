@@ -0,0 +1,38 @@
+package chat
+
+import (
+	"context"
+	"time"
+)
+
+// ReadMarker is the last message a user has read in a room.
+type ReadMarker struct {
+	RoomID    string `json:"room_id" bson:"room_id"`
+	UserID    string `json:"user_id" bson:"user_id"`
+	MessageID string `json:"message_id" bson:"message_id"`
+	// Timestamp is the time of the marked message, which orders markers.
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	// Audience holds who may learn about the marker, because the marked
+	// message is private. Nil means the whole room.
+	Audience []string `json:"-" bson:"-"`
+}
+
+func (m *ReadMarker) cursor() Cursor {
+	return Cursor{Timestamp: m.Timestamp, ID: m.MessageID}
+}
+
+// Unread is how many messages of a room a user has not read yet.
+type Unread struct {
+	Count int64 `json:"unread_count"`
+	// LastReadMessageID is empty while the user has read nothing.
+	LastReadMessageID string `json:"last_read_message_id,omitempty"`
+}
+
+type ReadMarkerRepository interface {
+	// FindReadMarker returns the user's marker, or nil if there is none.
+	FindReadMarker(ctx context.Context, roomID, userID string) (*ReadMarker, error)
+	// SaveReadMarker creates or replaces the user's marker.
+	SaveReadMarker(ctx context.Context, marker *ReadMarker) error
+	DeleteByRoomID(ctx context.Context, roomID string) error
+}
@@ -1,9 +1,54 @@
 package chat
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
+	// Create stores a new message. It fails with ErrDuplicateClientMsgID
+	// when the sender already used the message's client message ID in the
+	// room.
 	Create(ctx context.Context, message *Message) error
-	FindByRoomID(ctx context.Context, roomID string, limit, offset int) ([]*Message, error)
+	FindByID(ctx context.Context, id string) (*Message, error)
+	// FindByClientMsgID returns the sender's message with the client
+	// message ID, or nil if there is none.
+	FindByClientMsgID(ctx context.Context, roomID, userID, clientMsgID string) (*Message, error)
+	// FindPage returns up to query.Limit room messages in chronological
+	// order, leaving out private messages the viewer is not part of. They
+	// are the messages closest to the cursor, or the newest ones without
+	// a cursor. Offset is ignored.
+	FindPage(ctx context.Context, query MessageQuery) ([]*Message, error)
+	// FindByRoomID is FindPage with an offset from the newest message.
+	//
+	// Deprecated: use FindPage.
+	FindByRoomID(ctx context.Context, roomID, viewerID string, limit, offset int) ([]*Message, error)
+	// UpdateContent replaces the text of a message that is not deleted and
+	// appends the previous version to its history.
+	UpdateContent(ctx context.Context, id, message string, previous Revision) error
+	// MarkDeleted turns the message into a tombstone and unpins it.
+	MarkDeleted(ctx context.Context, id, deletedBy string, deletedAt time.Time) error
+	// FindReplies returns the replies to a message in chronological order.
+	FindReplies(ctx context.Context, parentID string) ([]*Message, error)
+	IncrementReplyCount(ctx context.Context, id string, delta int) error
+	// Pin pins a message unless its room already has maxPins pinned
+	// messages, which fails with ErrPinLimit. The limit holds when hosts
+	// pin at the same time. A message that is already pinned or deleted
+	// fails with ErrNotPinnable.
+	Pin(ctx context.Context, id, pinnedBy string, pinnedAt time.Time, maxPins int) error
+	Unpin(ctx context.Context, id string) error
+	// FindPinned returns the pinned messages of a room in the order they
+	// were pinned.
+	FindPinned(ctx context.Context, roomID string) ([]*Message, error)
+	// Stream calls fn with every room message the viewer may see, oldest
+	// first, stopping at the first error.
+	Stream(ctx context.Context, roomID, viewerID string, fn func(msg *Message) error) error
+	// Search runs a text search over the messages that are not deleted,
+	// leaving out private messages the viewer is not part of.
+	Search(ctx context.Context, search MessageSearch) ([]*Message, error)
+	// CountUnread counts the messages after the cursor, or all of them
+	// without one, that the viewer may see and did not send. Deleted
+	// messages are not counted.
+	CountUnread(ctx context.Context, roomID, viewerID string, after *Cursor) (int64, error)
 	DeleteByRoomID(ctx context.Context, roomID string) error
 }
@@ -0,0 +1,240 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/core/domain/user"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+	"github.com/meet-clone/backend/internal/pkg/logger"
+)
+
+// RetentionPolicy decides how long the chat of a room is kept after the
+// meeting ends. The room setting wins over the organization of the room's
+// creator, which wins over Default.
+type RetentionPolicy struct {
+	// Default applies to rooms without a more specific retention. Zero
+	// keeps chat forever.
+	Default time.Duration
+	// Organizations holds the retention of organizations by ID.
+	Organizations map[string]time.Duration
+}
+
+// needsOrganization reports whether the room's retention depends on the
+// organization of its creator.
+func (p RetentionPolicy) needsOrganization(rm *room.Room) bool {
+	return rm.Settings.ChatRetentionDays == 0 && len(p.Organizations) > 0
+}
+
+// retention returns how long the room's chat is kept, or zero for forever.
+func (p RetentionPolicy) retention(rm *room.Room, organizationID string) time.Duration {
+	if days := rm.Settings.ChatRetentionDays; days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	if d, ok := p.Organizations[organizationID]; ok && organizationID != "" {
+		return d
+	}
+	return p.Default
+}
+
+// RoomRetention looks up how long the chat of a single room is kept, for
+// data shared in the chat that must not be deleted before it.
+type RoomRetention struct {
+	policy RetentionPolicy
+	users  Users
+}
+
+func NewRoomRetention(policy RetentionPolicy, users Users) *RoomRetention {
+	return &RoomRetention{
+		policy: policy,
+		users:  users,
+	}
+}
+
+// ChatRetention returns how long the chat of the room is kept after the
+// meeting ends, or zero for forever.
+func (r *RoomRetention) ChatRetention(ctx context.Context, rm *room.Room) (time.Duration, error) {
+	var organizationID string
+	if r.policy.needsOrganization(rm) {
+		var err error
+		if organizationID, err = organizationOf(ctx, r.users, rm.CreatedBy); err != nil {
+			return 0, err
+		}
+	}
+	return r.policy.retention(rm, organizationID), nil
+}
+
+// RetentionRooms gives the purger access to ended rooms.
+type RetentionRooms interface {
+	GetRoomDetails(ctx context.Context, roomID string) (*room.Room, error)
+	ListRooms(ctx context.Context, filter room.ListFilter) (*room.RoomPage, error)
+	MarkChatPurged(ctx context.Context, roomID string, at time.Time) (*room.Room, error)
+}
+
+// RetentionAttachments deletes the files shared in a purged chat.
+type RetentionAttachments interface {
+	DeleteRoomAttachments(ctx context.Context, roomID string) (int, error)
+}
+
+// Users looks up the creators of rooms to find their organization.
+type Users interface {
+	GetByID(ctx context.Context, id string) (*user.User, error)
+}
+
+// retentionBatchSize is the page size used to walk ended rooms.
+const retentionBatchSize = 100
+
+// Purger deletes the chat of ended meetings once their retention passes,
+// except for rooms on legal hold.
+type Purger struct {
+	repo        Repository
+	markers     ReadMarkerRepository
+	attachments RetentionAttachments
+	rooms       RetentionRooms
+	users       Users
+	policy      RetentionPolicy
+	interval    time.Duration
+}
+
+func NewPurger(repo Repository, markers ReadMarkerRepository, attachments RetentionAttachments, rooms RetentionRooms, users Users, policy RetentionPolicy, interval time.Duration) *Purger {
+	return &Purger{
+		repo:        repo,
+		markers:     markers,
+		attachments: attachments,
+		rooms:       rooms,
+		users:       users,
+		policy:      policy,
+		interval:    interval,
+	}
+}
+
+// Run purges expired chat every interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Purge(ctx)
+		}
+	}
+}
+
+// Purge deletes the expired chat once.
+func (p *Purger) Purge(ctx context.Context) {
+	purged, err := p.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		logger.Error.Printf("Purger failed to purge chat: %v", err)
+	}
+	if purged > 0 {
+		logger.Info.Printf("Purger deleted the chat of %d rooms", purged)
+	}
+}
+
+// PurgeExpired deletes the chat of every ended room whose retention passed
+// by now and returns how many rooms were purged.
+func (p *Purger) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	organizations := make(map[string]string)
+	purged := 0
+
+	filter := room.ListFilter{
+		Status:   room.RoomStatusEnded,
+		WithChat: true,
+		Sort:     room.SortOldest,
+		Limit:    retentionBatchSize,
+	}
+	for {
+		page, err := p.rooms.ListRooms(ctx, filter)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, rm := range page.Rooms {
+			if rm.LegalHold {
+				continue
+			}
+
+			var organizationID string
+			if p.policy.needsOrganization(rm) {
+				if organizationID, err = p.organization(ctx, organizations, rm.CreatedBy); err != nil {
+					return purged, err
+				}
+			}
+
+			retention := p.policy.retention(rm, organizationID)
+			if retention <= 0 || now.Before(rm.EndedAt.Add(retention)) {
+				continue
+			}
+
+			if err := p.purgeRoom(ctx, rm.ID, now); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if page.NextCursor == "" {
+			return purged, nil
+		}
+		if filter.After, err = room.DecodeCursor(page.NextCursor); err != nil {
+			return purged, errors.NewInternalError("failed to list rooms", err)
+		}
+	}
+}
+
+// purgeRoom deletes the chat of a room with its attachments unless a legal hold was placed on
+// it since it was listed.
+func (p *Purger) purgeRoom(ctx context.Context, roomID string, now time.Time) error {
+	rm, err := p.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if rm.LegalHold {
+		return nil
+	}
+
+	if _, err := p.attachments.DeleteRoomAttachments(ctx, roomID); err != nil {
+		return err
+	}
+	if err := p.repo.DeleteByRoomID(ctx, roomID); err != nil {
+		return errors.NewInternalError("failed to delete chat", err)
+	}
+	if err := p.markers.DeleteByRoomID(ctx, roomID); err != nil {
+		return errors.NewInternalError("failed to delete read markers", err)
+	}
+
+	_, err = p.rooms.MarkChatPurged(ctx, roomID, now)
+	return err
+}
+
+// organization returns the organization of the user, caching lookups for
+// one purge.
+func (p *Purger) organization(ctx context.Context, cache map[string]string, userID string) (string, error) {
+	if id, ok := cache[userID]; ok {
+		return id, nil
+	}
+
+	id, err := organizationOf(ctx, p.users, userID)
+	if err != nil {
+		return "", err
+	}
+
+	cache[userID] = id
+	return id, nil
+}
+
+// organizationOf returns the organization of the user. Users that no
+// longer exist belong to none.
+func organizationOf(ctx context.Context, users Users, userID string) (string, error) {
+	u, err := users.GetByID(ctx, userID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type == errors.ErrorTypeNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return u.OrganizationID, nil
+}
@@ -0,0 +1,127 @@
+package chat
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchQuery selects the messages returned by Service.SearchMessages.
+type SearchQuery struct {
+	// Text is a MongoDB text search: words, "quoted phrases" and -excluded
+	// words.
+	Text     string
+	ViewerID string
+	// RoomID limits the search to one room. Empty searches every room the
+	// viewer created or joined.
+	RoomID string
+	From   time.Time
+	To     time.Time
+	// After continues a previous search after the given message.
+	After *Cursor
+	Limit int
+}
+
+// MessageSearch is a search as run by Repository.Search. Results are the
+// newest first.
+type MessageSearch struct {
+	Text     string
+	ViewerID string
+	RoomIDs  []string
+	From     time.Time
+	To       time.Time
+	After    *Cursor
+	Limit    int
+}
+
+// SearchResult is a message matching a search.
+type SearchResult struct {
+	Message   *Message `json:"message"`
+	RoomTitle string   `json:"room_title"`
+	// Fragments is the message text split into the parts that matched the
+	// search and the parts around them.
+	Fragments []Fragment `json:"fragments"`
+}
+
+// Fragment is a piece of a message text.
+type Fragment struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match"`
+}
+
+// SearchPage is one page of search results.
+type SearchPage struct {
+	Results    []*SearchResult `json:"results"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// highlight splits text into fragments, marking the words that match a
+// search term. Text search stems words, so a term matches every word it
+// starts, and a plural term also matches its singular.
+func highlight(text, query string) []Fragment {
+	pattern := highlightPattern(query)
+	if pattern == nil {
+		return []Fragment{{Text: text}}
+	}
+
+	fragments := []Fragment{}
+	last := 0
+	for _, match := range pattern.FindAllStringIndex(text, -1) {
+		// Only whole words count, so "link" does not light up "unlinked"
+		if before, _ := utf8.DecodeLastRuneInString(text[:match[0]]); match[0] > 0 && isWordRune(before) {
+			continue
+		}
+		if match[0] > last {
+			fragments = append(fragments, Fragment{Text: text[last:match[0]]})
+		}
+		fragments = append(fragments, Fragment{Text: text[match[0]:match[1]], Match: true})
+		last = match[1]
+	}
+	if last < len(text) {
+		fragments = append(fragments, Fragment{Text: text[last:]})
+	}
+	return fragments
+}
+
+func highlightPattern(query string) *regexp.Regexp {
+	var terms []string
+	for _, word := range searchTerms(query) {
+		if len(word) > 3 && strings.HasSuffix(word, "s") {
+			word = strings.TrimSuffix(word, "s")
+		}
+		terms = append(terms, regexp.QuoteMeta(word))
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)(` + strings.Join(terms, "|") + `)[\pL\pN]*`)
+}
+
+// searchTerms returns the words of a text search that results must or may
+// contain, leaving out excluded words.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !isWordRune(r)
+		})
+		if word != "" {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
@@ -2,44 +2,746 @@ package chat
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/meet-clone/backend/internal/core/domain/attachment"
+	"github.com/meet-clone/backend/internal/core/domain/room"
 	"github.com/meet-clone/backend/internal/pkg/errors"
 )
 
 type Service interface {
-	SendMessage(ctx context.Context, roomID, userID, userName, message string) (*Message, error)
-	GetMessages(ctx context.Context, roomID string, limit, offset int) ([]*Message, error)
+	// SendMessage stores and returns a message. A draft with a client
+	// message ID the sender already used fails with *Duplicate.
+	SendMessage(ctx context.Context, draft Draft) (*Message, error)
+	// GetMessages returns a page of the room messages the viewer may see.
+	GetMessages(ctx context.Context, query MessageQuery) (*MessagePage, error)
+	EditMessage(ctx context.Context, roomID, messageID, userID, message string) (*Message, error)
+	DeleteMessage(ctx context.Context, roomID, messageID, userID string) (*Message, error)
+	GetThread(ctx context.Context, roomID, messageID, viewerID string) (*Thread, error)
+	// SearchMessages searches the messages of every room the viewer created
+	// or joined.
+	SearchMessages(ctx context.Context, query SearchQuery) (*SearchPage, error)
+	// ExportMessages writes every message of the room the user may see to
+	// the transcript, oldest first, without loading them all at once. Only
+	// members of the room can export it.
+	ExportMessages(ctx context.Context, roomID, userID string, transcript Transcript) error
+	// MarkRead moves the user's read marker forward to the message. The
+	// current marker is returned when it is already past the message. The
+	// marker's Audience tells who may be told about it.
+	MarkRead(ctx context.Context, roomID, userID, messageID string) (*ReadMarker, error)
+	// GetUnread counts the messages of others after the user's read marker.
+	GetUnread(ctx context.Context, roomID, userID string) (*Unread, error)
+	// PinMessage pins a message of the room and returns the room's pinned
+	// messages. Only hosts can pin, and private messages cannot be pinned.
+	PinMessage(ctx context.Context, roomID, messageID, userID string) ([]*Message, error)
+	// UnpinMessage unpins a message and returns the remaining pins.
+	UnpinMessage(ctx context.Context, roomID, messageID, userID string) ([]*Message, error)
+	// GetPinnedMessages returns the pinned messages of a room in the order
+	// they were pinned.
+	GetPinnedMessages(ctx context.Context, roomID, viewerID string) ([]*Message, error)
+	// ModerateCommand runs a slash command through the same moderators as
+	// messages, so it counts toward slow mode and cannot carry blocked
+	// words. It returns the text as moderation left it.
+	ModerateCommand(ctx context.Context, rm *room.Room, userID, userName, text string) (string, error)
+}
+
+// Transcript receives an exported conversation.
+type Transcript interface {
+	// Begin is called once before the first message. Errors returned
+	// before Begin mean nothing was written.
+	Begin(rm *room.Room) error
+	Write(msg *Message) error
+	End() error
+}
+
+// Rooms gives the chat access to the rooms messages are sent in.
+type Rooms interface {
+	GetRoomDetails(ctx context.Context, roomID string) (*room.Room, error)
+	ListRooms(ctx context.Context, filter room.ListFilter) (*room.RoomPage, error)
+}
+
+// Attachments looks up files shared in chat messages.
+type Attachments interface {
+	GetAttachment(ctx context.Context, roomID, id, userID string) (*attachment.Attachment, error)
 }
 
 type service struct {
-	repo Repository
+	repo        Repository
+	markers     ReadMarkerRepository
+	rooms       Rooms
+	attachments Attachments
+	policy      Policy
+	moderators  []Moderator
 }
 
-func NewService(repo Repository) Service {
+// NewService returns the chat service. Messages pass the moderators in
+// order before they are saved.
+func NewService(repo Repository, markers ReadMarkerRepository, rooms Rooms, attachments Attachments, policy Policy, moderators ...Moderator) Service {
 	return &service{
-		repo: repo,
+		repo:        repo,
+		markers:     markers,
+		rooms:       rooms,
+		attachments: attachments,
+		policy:      policy,
+		moderators:  moderators,
 	}
 }
 
-func (s *service) SendMessage(ctx context.Context, roomID, userID, userName, message string) (*Message, error) {
-	if message == "" {
+func (s *service) SendMessage(ctx context.Context, draft Draft) (*Message, error) {
+	if draft.Message == "" && draft.AttachmentID == "" {
 		return nil, errors.NewValidationError("message cannot be empty")
 	}
+	if len(draft.ClientMsgID) > maxClientMsgIDLength {
+		return nil, errors.NewValidationError("client_msg_id must be at most 64 characters")
+	}
 
-	msg := NewMessage(roomID, userID, userName, message)
+	// A resent message is answered before moderation, so slow mode does
+	// not reject the retry of a message that got through
+	if draft.ClientMsgID != "" {
+		if err := s.checkDuplicate(ctx, draft); err != nil {
+			return nil, err
+		}
+	}
+
+	rm, err := s.rooms.GetRoomDetails(ctx, draft.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if !rm.IsActive() {
+		return nil, errors.NewValidationError("room has ended")
+	}
+	if !rm.IsParticipant(draft.UserID) {
+		return nil, errors.NewForbiddenError("only participants can send messages")
+	}
+	if rm.Settings.ChatDisabled {
+		return nil, errors.NewForbiddenError("chat is disabled in this room")
+	}
+
+	msg := NewMessage(draft.RoomID, draft.UserID, draft.UserName, draft.Message)
+	msg.ClientMsgID = draft.ClientMsgID
+
+	if draft.RecipientID != "" {
+		if rm.Settings.PrivateChatDisabled {
+			return nil, errors.NewForbiddenError("private chat is disabled in this room")
+		}
+		if draft.RecipientID == draft.UserID {
+			return nil, errors.NewValidationError("cannot send a private message to yourself")
+		}
+		if !rm.IsParticipant(draft.RecipientID) {
+			return nil, errors.NewValidationError("recipient is not in the room")
+		}
+		if draft.ParentID != "" {
+			return nil, errors.NewValidationError("private messages cannot be sent as replies")
+		}
+		msg.RecipientID = draft.RecipientID
+	}
+
+	if draft.AttachmentID != "" {
+		a, err := s.attachments.GetAttachment(ctx, draft.RoomID, draft.AttachmentID, draft.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if a.UploadedBy != draft.UserID {
+			return nil, errors.NewForbiddenError("only the uploader can share an attachment")
+		}
+		msg.Attachment = a
+	}
+
+	if draft.ParentID != "" {
+		parent, err := s.findInRoom(ctx, draft.RoomID, draft.ParentID)
+		if err != nil {
+			return nil, errors.NewValidationError("parent message not found in this room")
+		}
+		if parent.IsPrivate() {
+			return nil, errors.NewValidationError("cannot reply to a private message")
+		}
+		if parent.Deleted {
+			return nil, errors.NewValidationError("cannot reply to a deleted message")
+		}
+
+		// Threads are one level deep, so replying to a reply joins the
+		// thread of its parent
+		msg.ParentID = parent.ID
+		if parent.ParentID != "" {
+			msg.ParentID = parent.ParentID
+		}
+		if draft.Quote {
+			msg.Quote = parent.quote()
+		}
+	}
+
+	if err := s.moderate(ctx, &Submission{Room: rm, Message: msg}); err != nil {
+		return nil, err
+	}
+
+	// Mentions are parsed after moderation so masked names do not notify,
+	// and only the recipient can be mentioned in a private message
+	for _, m := range parseMentions(msg.Message, rm.GetActiveParticipants()) {
+		if m.UserID == msg.UserID || (msg.IsPrivate() && m.UserID != msg.RecipientID) {
+			continue
+		}
+		msg.Mentions = append(msg.Mentions, m)
+	}
 
 	if err := s.repo.Create(ctx, msg); err != nil {
+		// Another connection stored the same message in the meantime
+		if err == ErrDuplicateClientMsgID {
+			if err := s.checkDuplicate(ctx, draft); err != nil {
+				return nil, err
+			}
+		}
 		return nil, errors.NewInternalError("failed to save message", err)
 	}
 
+	if msg.ParentID != "" {
+		if err := s.repo.IncrementReplyCount(ctx, msg.ParentID, 1); err != nil {
+			return nil, errors.NewInternalError("failed to update reply count", err)
+		}
+	}
+
 	return msg, nil
 }
 
-func (s *service) GetMessages(ctx context.Context, roomID string, limit, offset int) ([]*Message, error) {
-	messages, err := s.repo.FindByRoomID(ctx, roomID, limit, offset)
+func (s *service) GetMessages(ctx context.Context, query MessageQuery) (*MessagePage, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultPageLimit
+	}
+	if query.Limit > maxPageLimit {
+		query.Limit = maxPageLimit
+	}
+	if query.Before != nil && query.After != nil {
+		return nil, errors.NewValidationError("before and after cannot be combined")
+	}
+	if query.Offset != nil {
+		if query.Before != nil || query.After != nil {
+			return nil, errors.NewValidationError("offset cannot be combined with a cursor")
+		}
+		if *query.Offset < 0 {
+			return nil, errors.NewValidationError("offset cannot be negative")
+		}
+	}
+	if err := s.requireMember(ctx, query.RoomID, query.ViewerID); err != nil {
+		return nil, err
+	}
+
+	var page *MessagePage
+	var err error
+	if query.Offset != nil {
+		page, err = s.offsetPage(ctx, query)
+	} else {
+		page, err = s.cursorPage(ctx, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, msg := range page.Messages {
+		msg.Redact()
+	}
+
+	return page, nil
+}
+
+func (s *service) cursorPage(ctx context.Context, query MessageQuery) (*MessagePage, error) {
+	// Fetch one extra message to learn whether the page is the last one in
+	// the direction of travel
+	limit := query.Limit
+	query.Limit++
+
+	messages, err := s.repo.FindPage(ctx, query)
 	if err != nil {
 		return nil, errors.NewInternalError("failed to retrieve messages", err)
 	}
 
-	return messages, nil
+	more := len(messages) > limit
+	if more && query.After != nil {
+		messages = messages[:limit]
+	} else if more {
+		messages = messages[1:]
+	}
+
+	page := &MessagePage{Messages: messages}
+	if len(messages) == 0 {
+		return page, nil
+	}
+
+	first, last := cursorOf(messages[0]), cursorOf(messages[len(messages)-1])
+	switch {
+	case query.After != nil:
+		page.PrevCursor = first.Encode()
+		if more {
+			page.NextCursor = last.Encode()
+		}
+	case query.Before != nil:
+		page.NextCursor = last.Encode()
+		if more {
+			page.PrevCursor = first.Encode()
+		}
+	default:
+		if more {
+			page.PrevCursor = first.Encode()
+		}
+	}
+
+	return page, nil
+}
+
+// offsetPage serves the deprecated offset mode, which skips the newest
+// messages.
+func (s *service) offsetPage(ctx context.Context, query MessageQuery) (*MessagePage, error) {
+	messages, err := s.repo.FindByRoomID(ctx, query.RoomID, query.ViewerID, query.Limit, *query.Offset)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to retrieve messages", err)
+	}
+
+	return &MessagePage{Messages: messages}, nil
+}
+
+// EditMessage replaces the text of one of the user's own messages, keeping
+// the previous text in the message history.
+func (s *service) EditMessage(ctx context.Context, roomID, messageID, userID, message string) (*Message, error) {
+	if message == "" {
+		return nil, errors.NewValidationError("message cannot be empty")
+	}
+
+	msg, err := s.findInRoom(ctx, roomID, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.UserID != userID {
+		return nil, errors.NewForbiddenError("only the author can edit a message")
+	}
+	if msg.Deleted {
+		return nil, errors.NewValidationError("message has been deleted")
+	}
+
+	rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Edits pass moderation too, so they cannot sneak in blocked words
+	edited := *msg
+	edited.Message = message
+	if err := s.moderate(ctx, &Submission{Room: rm, Message: &edited, Edit: true}); err != nil {
+		return nil, err
+	}
+	message = edited.Message
+
+	previous := Revision{Message: msg.Message, ReplacedAt: time.Now()}
+	if err := s.repo.UpdateContent(ctx, msg.ID, message, previous); err != nil {
+		return nil, errors.NewInternalError("failed to edit message", err)
+	}
+
+	msg.History = append(msg.History, previous)
+	msg.Message = message
+	msg.EditedAt = previous.ReplacedAt
+
+	return msg, nil
+}
+
+// DeleteMessage turns a message into a tombstone. Authors can delete their
+// own messages and room hosts can delete any message.
+func (s *service) DeleteMessage(ctx context.Context, roomID, messageID, userID string) (*Message, error) {
+	msg, err := s.findInRoom(ctx, roomID, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.Deleted {
+		return nil, errors.NewValidationError("message has already been deleted")
+	}
+
+	if !msg.VisibleTo(userID) {
+		return nil, errors.NewNotFoundError("message not found")
+	}
+
+	if msg.UserID != userID {
+		rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+		if err != nil {
+			return nil, err
+		}
+		if !rm.IsHost(userID) {
+			return nil, errors.NewForbiddenError("only the author or a host can delete a message")
+		}
+	}
+
+	msg.Deleted = true
+	msg.DeletedAt = time.Now()
+	msg.DeletedBy = userID
+	msg.Pinned = false
+	msg.PinnedAt = time.Time{}
+	msg.PinnedBy = ""
+
+	if err := s.repo.MarkDeleted(ctx, msg.ID, msg.DeletedBy, msg.DeletedAt); err != nil {
+		return nil, errors.NewInternalError("failed to delete message", err)
+	}
+
+	msg.Redact()
+	return msg, nil
+}
+
+func (s *service) GetThread(ctx context.Context, roomID, messageID, viewerID string) (*Thread, error) {
+	if err := s.requireMember(ctx, roomID, viewerID); err != nil {
+		return nil, err
+	}
+
+	parent, err := s.findInRoom(ctx, roomID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if !parent.VisibleTo(viewerID) {
+		return nil, errors.NewNotFoundError("message not found")
+	}
+
+	replies, err := s.repo.FindReplies(ctx, parent.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to retrieve thread", err)
+	}
+
+	parent.Redact()
+	for _, reply := range replies {
+		reply.Redact()
+	}
+
+	return &Thread{Parent: parent, Replies: replies}, nil
+}
+
+func (s *service) SearchMessages(ctx context.Context, query SearchQuery) (*SearchPage, error) {
+	if strings.TrimSpace(query.Text) == "" {
+		return nil, errors.NewValidationError("search query is required")
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+
+	titles, err := s.memberRooms(ctx, query.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	roomIDs := make([]string, 0, len(titles))
+	if query.RoomID != "" {
+		if _, ok := titles[query.RoomID]; !ok {
+			return nil, errors.NewNotFoundError("room not found")
+		}
+		roomIDs = append(roomIDs, query.RoomID)
+	} else {
+		for id := range titles {
+			roomIDs = append(roomIDs, id)
+		}
+	}
+
+	page := &SearchPage{Results: []*SearchResult{}}
+	if len(roomIDs) == 0 {
+		return page, nil
+	}
+
+	// Fetch one extra message to learn whether there is a next page
+	messages, err := s.repo.Search(ctx, MessageSearch{
+		Text:     query.Text,
+		ViewerID: query.ViewerID,
+		RoomIDs:  roomIDs,
+		From:     query.From,
+		To:       query.To,
+		After:    query.After,
+		Limit:    query.Limit + 1,
+	})
+	if err != nil {
+		return nil, errors.NewInternalError("failed to search messages", err)
+	}
+
+	if len(messages) > query.Limit {
+		messages = messages[:query.Limit]
+		page.NextCursor = cursorOf(messages[query.Limit-1]).Encode()
+	}
+
+	for _, msg := range messages {
+		page.Results = append(page.Results, &SearchResult{
+			Message:   msg,
+			RoomTitle: titles[msg.RoomID],
+			Fragments: highlight(msg.Message, query.Text),
+		})
+	}
+
+	return page, nil
+}
+
+func (s *service) ExportMessages(ctx context.Context, roomID, userID string, transcript Transcript) error {
+	rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if !rm.IsMember(userID) {
+		return errors.NewForbiddenError("only members of the room can export its chat")
+	}
+
+	if err := transcript.Begin(rm); err != nil {
+		return err
+	}
+
+	err = s.repo.Stream(ctx, roomID, userID, func(msg *Message) error {
+		msg.Redact()
+		return transcript.Write(msg)
+	})
+	if err != nil {
+		return err
+	}
+
+	return transcript.End()
+}
+
+func (s *service) MarkRead(ctx context.Context, roomID, userID, messageID string) (*ReadMarker, error) {
+	if err := s.requireMember(ctx, roomID, userID); err != nil {
+		return nil, err
+	}
+
+	msg, err := s.findInRoom(ctx, roomID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.VisibleTo(userID) {
+		return nil, errors.NewNotFoundError("message not found")
+	}
+
+	current, err := s.markers.FindReadMarker(ctx, roomID, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to load read marker", err)
+	}
+
+	// Markers only move forward, so a late request from another device
+	// cannot mark messages as unread again. Nothing changed for others, so
+	// only the reader hears about it.
+	if current != nil && !cursorOf(msg).after(current.cursor()) {
+		current.Audience = []string{userID}
+		return current, nil
+	}
+
+	marker := &ReadMarker{
+		RoomID:    roomID,
+		UserID:    userID,
+		MessageID: msg.ID,
+		Timestamp: msg.Timestamp,
+		UpdatedAt: time.Now(),
+	}
+	if msg.IsPrivate() {
+		marker.Audience = []string{msg.UserID, msg.RecipientID}
+	}
+	if err := s.markers.SaveReadMarker(ctx, marker); err != nil {
+		return nil, errors.NewInternalError("failed to save read marker", err)
+	}
+
+	return marker, nil
+}
+
+func (s *service) GetUnread(ctx context.Context, roomID, userID string) (*Unread, error) {
+	if err := s.requireMember(ctx, roomID, userID); err != nil {
+		return nil, err
+	}
+
+	marker, err := s.markers.FindReadMarker(ctx, roomID, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to load read marker", err)
+	}
+
+	unread := &Unread{}
+	var after *Cursor
+	if marker != nil {
+		c := marker.cursor()
+		after = &c
+		unread.LastReadMessageID = marker.MessageID
+	}
+
+	if unread.Count, err = s.repo.CountUnread(ctx, roomID, userID, after); err != nil {
+		return nil, errors.NewInternalError("failed to count unread messages", err)
+	}
+
+	return unread, nil
+}
+
+func (s *service) PinMessage(ctx context.Context, roomID, messageID, userID string) ([]*Message, error) {
+	if err := s.requireHost(ctx, roomID, userID, "only hosts can pin messages"); err != nil {
+		return nil, err
+	}
+
+	msg, err := s.findInRoom(ctx, roomID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.IsPrivate() {
+		if !msg.VisibleTo(userID) {
+			return nil, errors.NewNotFoundError("message not found")
+		}
+		return nil, errors.NewValidationError("private messages cannot be pinned")
+	}
+	if msg.Deleted {
+		return nil, errors.NewValidationError("deleted messages cannot be pinned")
+	}
+	if msg.Pinned {
+		return nil, errors.NewValidationError("message is already pinned")
+	}
+
+	// Counting first also holds the limit when it was lowered below the
+	// pins a room already has; the repository holds it against races
+	pinned, err := s.repo.FindPinned(ctx, roomID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to retrieve pinned messages", err)
+	}
+	if len(pinned) >= s.policy.MaxPins {
+		return nil, s.pinLimitError()
+	}
+
+	switch err := s.repo.Pin(ctx, msg.ID, userID, time.Now(), s.policy.MaxPins); err {
+	case nil:
+	case ErrPinLimit:
+		return nil, s.pinLimitError()
+	case ErrNotPinnable:
+		return nil, errors.NewValidationError("message was pinned or deleted in the meantime")
+	default:
+		return nil, errors.NewInternalError("failed to pin message", err)
+	}
+
+	if pinned, err = s.repo.FindPinned(ctx, roomID); err != nil {
+		return nil, errors.NewInternalError("failed to retrieve pinned messages", err)
+	}
+	return pinned, nil
+}
+
+// pinLimitError tells a host that the room has no free pin.
+func (s *service) pinLimitError() error {
+	return errors.NewValidationError(fmt.Sprintf("at most %d messages can be pinned", s.policy.MaxPins))
+}
+
+func (s *service) UnpinMessage(ctx context.Context, roomID, messageID, userID string) ([]*Message, error) {
+	if err := s.requireHost(ctx, roomID, userID, "only hosts can unpin messages"); err != nil {
+		return nil, err
+	}
+
+	msg, err := s.findInRoom(ctx, roomID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.Pinned {
+		return nil, errors.NewValidationError("message is not pinned")
+	}
+
+	if err := s.repo.Unpin(ctx, msg.ID); err != nil {
+		return nil, errors.NewInternalError("failed to unpin message", err)
+	}
+
+	pinned, err := s.repo.FindPinned(ctx, roomID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to retrieve pinned messages", err)
+	}
+	return pinned, nil
+}
+
+func (s *service) GetPinnedMessages(ctx context.Context, roomID, viewerID string) ([]*Message, error) {
+	if err := s.requireMember(ctx, roomID, viewerID); err != nil {
+		return nil, err
+	}
+
+	pinned, err := s.repo.FindPinned(ctx, roomID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to retrieve pinned messages", err)
+	}
+	return pinned, nil
+}
+
+// requireMember fails unless the user created or joined the room.
+func (s *service) requireMember(ctx context.Context, roomID, userID string) error {
+	rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if !rm.IsMember(userID) {
+		return errors.NewForbiddenError("only members of the room can read its chat")
+	}
+	return nil
+}
+
+// requireHost fails with message unless the user hosts the active room.
+func (s *service) requireHost(ctx context.Context, roomID, userID, message string) error {
+	rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if !rm.IsActive() {
+		return errors.NewValidationError("room has ended")
+	}
+	if !rm.IsHost(userID) {
+		return errors.NewForbiddenError(message)
+	}
+	return nil
+}
+
+// memberRoomsBatchSize is the page size used to walk a user's rooms.
+const memberRoomsBatchSize = 100
+
+// memberRooms returns the titles of the rooms the user created or joined,
+// by room ID.
+func (s *service) memberRooms(ctx context.Context, userID string) (map[string]string, error) {
+	titles := make(map[string]string)
+	filter := room.ListFilter{ViewerID: userID, Limit: memberRoomsBatchSize}
+	for {
+		page, err := s.rooms.ListRooms(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, rm := range page.Rooms {
+			titles[rm.ID] = rm.Title
+		}
+		if page.NextCursor == "" {
+			return titles, nil
+		}
+		if filter.After, err = room.DecodeCursor(page.NextCursor); err != nil {
+			return nil, errors.NewInternalError("failed to list rooms", err)
+		}
+	}
+}
+
+// checkDuplicate fails with *Duplicate when the sender already stored a
+// message with the draft's client message ID.
+func (s *service) checkDuplicate(ctx context.Context, draft Draft) error {
+	existing, err := s.repo.FindByClientMsgID(ctx, draft.RoomID, draft.UserID, draft.ClientMsgID)
+	if err != nil {
+		return errors.NewInternalError("failed to look up message", err)
+	}
+	if existing != nil {
+		existing.Redact()
+		return &Duplicate{Message: existing}
+	}
+	return nil
+}
+
+func (s *service) ModerateCommand(ctx context.Context, rm *room.Room, userID, userName, text string) (string, error) {
+	msg := NewMessage(rm.ID, userID, userName, text)
+	if err := s.moderate(ctx, &Submission{Room: rm, Message: msg}); err != nil {
+		return "", err
+	}
+	return msg.Message, nil
+}
+
+func (s *service) moderate(ctx context.Context, sub *Submission) error {
+	for _, m := range s.moderators {
+		if err := m.Moderate(ctx, sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findInRoom loads a message and checks that it belongs to the room.
+func (s *service) findInRoom(ctx context.Context, roomID, messageID string) (*Message, error) {
+	msg, err := s.repo.FindByID(ctx, messageID)
+	if err != nil || msg.RoomID != roomID {
+		return nil, errors.NewNotFoundError("message not found")
+	}
+	return msg, nil
 }
@@ -0,0 +1,276 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+const (
+	maxPollOptions = 10
+	maxTimer       = 3 * time.Hour
+	maxDice        = 20
+	maxDieSides    = 1000
+)
+
+// ChatMuter mutes the chat of participants for /mute and /unmute.
+type ChatMuter interface {
+	SetChatMuted(ctx context.Context, roomID, userID, targetID string, muted bool) (*room.Room, error)
+}
+
+// RegisterBuiltins adds the commands every server offers.
+func RegisterBuiltins(registry *Registry, rooms ChatMuter) error {
+	for _, cmd := range []Command{
+		Help(registry),
+		Poll(),
+		Timer(),
+		Roll(),
+		Mute(rooms, true),
+		Mute(rooms, false),
+	} {
+		if err := registry.Register(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HelpEntry describes a command in the /help response.
+type HelpEntry struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+}
+
+// Help lists the commands the caller is allowed to run.
+func Help(registry *Registry) Command {
+	return Command{
+		Name:        "help",
+		Usage:       "/help",
+		Description: "List the available commands",
+		Role:        room.RoleParticipant,
+		Run: func(ctx context.Context, inv *Invocation) (*Response, error) {
+			var entries []HelpEntry
+			var lines []string
+			for _, cmd := range registry.Commands() {
+				if !allowed(cmd, inv.Room, inv.UserID) {
+					continue
+				}
+				entries = append(entries, HelpEntry{Name: cmd.Name, Usage: cmd.Usage, Description: cmd.Description})
+				lines = append(lines, cmd.Usage+" - "+cmd.Description)
+			}
+
+			return &Response{
+				Audience: AudienceCaller,
+				Text:     strings.Join(lines, "\n"),
+				Data:     map[string]interface{}{"commands": entries},
+			}, nil
+		},
+	}
+}
+
+// Poll asks the room a question with options separated by "|".
+func Poll() Command {
+	return Command{
+		Name:        "poll",
+		Usage:       "/poll Question | Option | Option",
+		Description: "Ask the room a question",
+		Role:        room.RoleParticipant,
+		Run: func(ctx context.Context, inv *Invocation) (*Response, error) {
+			var parts []string
+			for _, part := range strings.Split(inv.Args, "|") {
+				if part = strings.TrimSpace(part); part != "" {
+					parts = append(parts, part)
+				}
+			}
+			if len(parts) < 3 {
+				return nil, errors.NewValidationError("usage: /poll Question | Option | Option")
+			}
+			question, options := parts[0], parts[1:]
+			if len(options) > maxPollOptions {
+				return nil, errors.NewValidationError(fmt.Sprintf("a poll can have at most %d options", maxPollOptions))
+			}
+
+			return &Response{
+				Text: fmt.Sprintf("%s asks: %s (%s)", inv.UserName, question, strings.Join(options, ", ")),
+				Data: map[string]interface{}{
+					"poll_id":  uuid.New().String(),
+					"question": question,
+					"options":  options,
+				},
+			}, nil
+		},
+	}
+}
+
+// Timer starts a countdown everyone in the room sees. A bare number is a
+// number of minutes.
+func Timer() Command {
+	return Command{
+		Name:        "timer",
+		Usage:       "/timer 5m",
+		Description: "Start a countdown for the room",
+		Role:        room.RoleHost,
+		Run: func(ctx context.Context, inv *Invocation) (*Response, error) {
+			duration, err := parseTimer(inv.Args)
+			if err != nil {
+				return nil, err
+			}
+
+			endsAt := time.Now().Add(duration)
+			return &Response{
+				Text: fmt.Sprintf("%s started a %s timer", inv.UserName, duration),
+				Data: map[string]interface{}{
+					"duration_seconds": int64(duration / time.Second),
+					"ends_at":          endsAt,
+				},
+			}, nil
+		},
+	}
+}
+
+func parseTimer(args string) (time.Duration, error) {
+	if args == "" {
+		return 0, errors.NewValidationError("usage: /timer 5m")
+	}
+
+	var duration time.Duration
+	if minutes, err := strconv.Atoi(args); err == nil {
+		duration = time.Duration(minutes) * time.Minute
+	} else if duration, err = time.ParseDuration(args); err != nil {
+		return 0, errors.NewValidationError("timer must be a duration such as 90s or 5m")
+	}
+
+	if duration < time.Second || duration > maxTimer {
+		return 0, errors.NewValidationError(fmt.Sprintf("timer must be between 1s and %s", maxTimer))
+	}
+	return duration.Round(time.Second), nil
+}
+
+// Roll rolls dice: /roll is 1d100, /roll 20 is 1d20 and /roll 2d6 adds up
+// two six-sided dice.
+func Roll() Command {
+	return Command{
+		Name:        "roll",
+		Usage:       "/roll [2d6]",
+		Description: "Roll dice",
+		Role:        room.RoleParticipant,
+		Run: func(ctx context.Context, inv *Invocation) (*Response, error) {
+			dice, sides, err := parseDice(inv.Args)
+			if err != nil {
+				return nil, err
+			}
+
+			rolls := make([]int, dice)
+			total := 0
+			for i := range rolls {
+				rolls[i] = rand.Intn(sides) + 1
+				total += rolls[i]
+			}
+
+			return &Response{
+				Text: fmt.Sprintf("%s rolled %d (%dd%d)", inv.UserName, total, dice, sides),
+				Data: map[string]interface{}{
+					"dice":  dice,
+					"sides": sides,
+					"rolls": rolls,
+					"total": total,
+				},
+			}, nil
+		},
+	}
+}
+
+func parseDice(args string) (dice, sides int, err error) {
+	if args == "" {
+		return 1, 100, nil
+	}
+
+	count, size, ok := strings.Cut(strings.ToLower(args), "d")
+	if !ok {
+		count, size = "1", args
+	} else if count == "" {
+		count = "1"
+	}
+
+	dice, err1 := strconv.Atoi(count)
+	sides, err2 := strconv.Atoi(size)
+	if err1 != nil || err2 != nil || dice < 1 || sides < 2 {
+		return 0, 0, errors.NewValidationError("usage: /roll, /roll 20 or /roll 2d6")
+	}
+	if dice > maxDice || sides > maxDieSides {
+		return 0, 0, errors.NewValidationError(fmt.Sprintf("at most %d dice of %d sides", maxDice, maxDieSides))
+	}
+	return dice, sides, nil
+}
+
+// Mute stops, or with muted set to false allows, a participant's chat
+// messages. It is registered as /mute and /unmute.
+func Mute(rooms ChatMuter, muted bool) Command {
+	name, verb := "mute", "muted"
+	if !muted {
+		name, verb = "unmute", "unmuted"
+	}
+
+	return Command{
+		Name:        name,
+		Usage:       "/" + name + " @name",
+		Description: "Stop or allow a participant's chat messages",
+		Role:        room.RoleHost,
+		Run: func(ctx context.Context, inv *Invocation) (*Response, error) {
+			target, err := findParticipant(inv.Room, inv.Args)
+			if err != nil {
+				return nil, err
+			}
+
+			rm, err := rooms.SetChatMuted(ctx, inv.Room.ID, inv.UserID, target.UserID, muted)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Response{
+				Text: fmt.Sprintf("%s %s the chat of %s", inv.UserName, verb, target.Name),
+				Data: map[string]interface{}{
+					"user_id": target.UserID,
+					"muted":   muted,
+				},
+				Room:     rm,
+				Affected: []string{target.UserID},
+			}, nil
+		},
+	}
+}
+
+// findParticipant resolves "@name" or a user ID to an active participant.
+func findParticipant(rm *room.Room, args string) (*room.Participant, error) {
+	name := strings.TrimSpace(strings.TrimPrefix(args, "@"))
+	if name == "" {
+		return nil, errors.NewValidationError("name a participant, such as @Alice")
+	}
+
+	var found *room.Participant
+	for _, p := range rm.GetActiveParticipants() {
+		if p.UserID == name {
+			p := p
+			return &p, nil
+		}
+		if strings.EqualFold(p.Name, name) {
+			if found != nil {
+				return nil, errors.NewValidationError(fmt.Sprintf("more than one participant is called %s", name))
+			}
+			p := p
+			found = &p
+		}
+	}
+	if found == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("no participant called %s", name))
+	}
+	return found, nil
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+// Audience is who receives the response to a command.
+type Audience string
+
+const (
+	// AudienceRoom shares the response with everyone in the room.
+	AudienceRoom Audience = "room"
+	// AudienceCaller sends the response only to the client that ran the
+	// command.
+	AudienceCaller Audience = "caller"
+)
+
+// Command is a chat command such as /roll, run when a chat message starts
+// with a slash and its name.
+type Command struct {
+	// Name is the command without its slash, in lower case.
+	Name        string
+	Usage       string
+	Description string
+	// Role is the participant role needed to run the command. Hosts can
+	// run every command.
+	Role room.ParticipantRole
+	Run  Handler
+}
+
+// Handler carries out a command. Errors are reported to the caller only.
+type Handler func(ctx context.Context, inv *Invocation) (*Response, error)
+
+// Invocation is a command being run by a participant.
+type Invocation struct {
+	Room     *room.Room
+	UserID   string
+	UserName string
+	Name     string
+	// Args is the text after the command name, trimmed.
+	Args string
+}
+
+// Response is the structured result of a command. Clients render it by
+// its command name and data rather than as a chat message.
+type Response struct {
+	Command  string   `json:"command"`
+	UserID   string   `json:"user_id"`
+	UserName string   `json:"user_name"`
+	Audience Audience `json:"audience"`
+	// Text is a plain rendering for clients that do not know the command.
+	Text string      `json:"text"`
+	Data interface{} `json:"data,omitempty"`
+	// Room is the room after the command changed it, and Affected are the
+	// participants whose moderation state changed.
+	Room     *room.Room `json:"-"`
+	Affected []string   `json:"-"`
+}
+
+// Parse splits a chat message into a command name and its arguments. Text
+// that does not start with a single slash and a name is not a command, so
+// "//" lets users send messages starting with a slash.
+func Parse(text string) (name, args string, ok bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") || strings.HasPrefix(text, "//") {
+		return "", "", false
+	}
+
+	name, args, _ = strings.Cut(text[1:], " ")
+	if name == "" {
+		return "", "", false
+	}
+	return strings.ToLower(name), strings.TrimSpace(args), true
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// Registry holds the commands users can run. Custom commands are added
+// with Register next to the built-in ones.
+type Registry struct {
+	mu       sync.RWMutex
+	commands map[string]Command
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		commands: make(map[string]Command),
+	}
+}
+
+// Register adds a command. Names are unique, so a custom command cannot
+// replace a built-in one by accident.
+func (r *Registry) Register(cmd Command) error {
+	if !namePattern.MatchString(cmd.Name) {
+		return fmt.Errorf("invalid command name %q", cmd.Name)
+	}
+	if cmd.Run == nil {
+		return fmt.Errorf("command /%s has no handler", cmd.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.commands[cmd.Name]; ok {
+		return fmt.Errorf("command /%s is already registered", cmd.Name)
+	}
+	r.commands[cmd.Name] = cmd
+	return nil
+}
+
+// Lookup returns the command with the name.
+func (r *Registry) Lookup(name string) (Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cmd, ok := r.commands[name]
+	return cmd, ok
+}
+
+// Commands returns every command sorted by name.
+func (r *Registry) Commands() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	commands := make([]Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		commands = append(commands, cmd)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+	return commands
+}
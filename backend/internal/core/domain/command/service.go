@@ -0,0 +1,102 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+type Service interface {
+	// Execute runs the command in a chat message. The caller must be in
+	// the room and have the role the command needs.
+	Execute(ctx context.Context, roomID, userID, userName, text string) (*Response, error)
+}
+
+// Rooms gives commands access to the room they are run in.
+type Rooms interface {
+	GetRoomDetails(ctx context.Context, roomID string) (*room.Room, error)
+}
+
+// Moderation checks command text against the chat rules, such as the
+// length limit, the word filter and slow mode, and returns the text as the
+// rules left it.
+type Moderation interface {
+	ModerateCommand(ctx context.Context, rm *room.Room, userID, userName, text string) (string, error)
+}
+
+type service struct {
+	registry   *Registry
+	rooms      Rooms
+	moderation Moderation
+}
+
+func NewService(registry *Registry, rooms Rooms, moderation Moderation) Service {
+	return &service{
+		registry:   registry,
+		rooms:      rooms,
+		moderation: moderation,
+	}
+}
+
+func (s *service) Execute(ctx context.Context, roomID, userID, userName, text string) (*Response, error) {
+	name, args, ok := Parse(text)
+	if !ok {
+		return nil, errors.NewValidationError("not a command")
+	}
+
+	cmd, ok := s.registry.Lookup(name)
+	if !ok {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("unknown command /%s, try /help", name))
+	}
+
+	rm, err := s.rooms.GetRoomDetails(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !rm.IsActive() {
+		return nil, errors.NewValidationError("room has ended")
+	}
+	if !rm.IsParticipant(userID) {
+		return nil, errors.NewForbiddenError("only participants can use commands")
+	}
+	if !allowed(cmd, rm, userID) {
+		return nil, errors.NewForbiddenError(fmt.Sprintf("only hosts can use /%s", cmd.Name))
+	}
+
+	// Commands are typed into the chat, so they pass the chat moderators
+	// like messages do. Hosts can still run commands while chat is off.
+	if rm.Settings.ChatDisabled && !rm.IsHost(userID) {
+		return nil, errors.NewForbiddenError("chat is disabled in this room")
+	}
+	text, err = s.moderation.ModerateCommand(ctx, rm, userID, userName, text)
+	if err != nil {
+		return nil, err
+	}
+	_, args, _ = Parse(text)
+
+	resp, err := cmd.Run(ctx, &Invocation{
+		Room:     rm,
+		UserID:   userID,
+		UserName: userName,
+		Name:     cmd.Name,
+		Args:     args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Command = cmd.Name
+	resp.UserID = userID
+	resp.UserName = userName
+	if resp.Audience == "" {
+		resp.Audience = AudienceRoom
+	}
+	return resp, nil
+}
+
+// allowed reports whether the user's role lets them run the command.
+func allowed(cmd Command, rm *room.Room, userID string) bool {
+	return cmd.Role != room.RoleHost || rm.IsHost(userID)
+}
@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+type Type string
+
+const (
+	// TypeMention tells a user they were mentioned in a chat message.
+	TypeMention Type = "mention"
+)
+
+// Notification is an entry in a user's inbox, kept for events they missed
+// while not connected.
+type Notification struct {
+	ID     string `json:"id" bson:"_id"`
+	UserID string `json:"user_id" bson:"user_id"`
+	Type   Type   `json:"type" bson:"type"`
+	RoomID string `json:"room_id" bson:"room_id"`
+	// MessageID is the chat message the notification is about, if any.
+	MessageID string `json:"message_id,omitempty" bson:"message_id,omitempty"`
+	// ActorID and ActorName identify who caused the notification.
+	ActorID   string    `json:"actor_id" bson:"actor_id"`
+	ActorName string    `json:"actor_name" bson:"actor_name"`
+	Text      string    `json:"text" bson:"text"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	ReadAt    time.Time `json:"read_at,omitempty" bson:"read_at,omitempty"`
+}
+
+func NewNotification(userID string, notificationType Type, roomID string) *Notification {
+	return &Notification{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Type:      notificationType,
+		RoomID:    roomID,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (n *Notification) IsRead() bool {
+	return !n.ReadAt.IsZero()
+}
+
+// maxTextLength bounds the excerpt of the message kept in a notification.
+const maxTextLength = 280
+
+func excerpt(text string) string {
+	runes := []rune(text)
+	if len(runes) > maxTextLength {
+		return string(runes[:maxTextLength]) + "…"
+	}
+	return text
+}
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ListFilter selects the notifications of a user, newest first.
+type ListFilter struct {
+	UserID     string
+	UnreadOnly bool
+	// After continues a previous listing after the given notification.
+	After *Cursor
+	Limit int
+}
+
+// Cursor identifies a position in a notification listing.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid cursor")
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, errors.NewValidationError("invalid cursor")
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid cursor")
+	}
+
+	return &Cursor{CreatedAt: time.Unix(0, n).UTC(), ID: id}, nil
+}
+
+// Page is one page of a user's notifications.
+type Page struct {
+	Notifications []*Notification `json:"notifications"`
+	UnreadCount   int64           `json:"unread_count"`
+	NextCursor    string          `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,16 @@
+package notification
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, notification *Notification) error
+	List(ctx context.Context, filter ListFilter) ([]*Notification, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead marks one of the user's notifications as read. It reports
+	// whether the notification exists.
+	MarkRead(ctx context.Context, userID, id string, readAt time.Time) (bool, error)
+	MarkAllRead(ctx context.Context, userID string, readAt time.Time) error
+}
@@ -0,0 +1,100 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+type Service interface {
+	// NotifyMention records that a user was mentioned in a chat message.
+	NotifyMention(ctx context.Context, mention Mention) (*Notification, error)
+	List(ctx context.Context, filter ListFilter) (*Page, error)
+	MarkRead(ctx context.Context, userID, id string) error
+	MarkAllRead(ctx context.Context, userID string) error
+}
+
+// Mention describes a chat message mentioning a user.
+type Mention struct {
+	UserID    string
+	RoomID    string
+	MessageID string
+	ActorID   string
+	ActorName string
+	Text      string
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{
+		repo: repo,
+	}
+}
+
+func (s *service) NotifyMention(ctx context.Context, mention Mention) (*Notification, error) {
+	n := NewNotification(mention.UserID, TypeMention, mention.RoomID)
+	n.MessageID = mention.MessageID
+	n.ActorID = mention.ActorID
+	n.ActorName = mention.ActorName
+	n.Text = excerpt(mention.Text)
+
+	if err := s.repo.Create(ctx, n); err != nil {
+		return nil, errors.NewInternalError("failed to save notification", err)
+	}
+
+	return n, nil
+}
+
+func (s *service) List(ctx context.Context, filter ListFilter) (*Page, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+
+	// Fetch one extra notification to learn whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	notifications, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to list notifications", err)
+	}
+
+	unread, err := s.repo.CountUnread(ctx, filter.UserID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to count notifications", err)
+	}
+
+	page := &Page{Notifications: notifications, UnreadCount: unread}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		last := page.Notifications[limit-1]
+		page.NextCursor = Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	return page, nil
+}
+
+func (s *service) MarkRead(ctx context.Context, userID, id string) error {
+	found, err := s.repo.MarkRead(ctx, userID, id, time.Now())
+	if err != nil {
+		return errors.NewInternalError("failed to mark notification as read", err)
+	}
+	if !found {
+		return errors.NewNotFoundError("notification not found")
+	}
+	return nil
+}
+
+func (s *service) MarkAllRead(ctx context.Context, userID string) error {
+	if err := s.repo.MarkAllRead(ctx, userID, time.Now()); err != nil {
+		return errors.NewInternalError("failed to mark notifications as read", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package room
+
+import (
+	"sort"
+	"time"
+)
+
+// AttendanceReport summarizes who attended a room and for how long.
+type AttendanceReport struct {
+	RoomID      string             `json:"room_id"`
+	Status      RoomStatus         `json:"status"`
+	CreatedAt   time.Time          `json:"created_at"`
+	EndedAt     time.Time          `json:"ended_at,omitempty"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Attendees   []AttendanceRecord `json:"attendees"`
+	Reactions   map[string]int     `json:"reactions"`
+}
+
+// AttendanceRecord is the attendance of a single user in a room.
+type AttendanceRecord struct {
+	UserID        string    `json:"user_id"`
+	Name          string    `json:"name"`
+	Joins         int       `json:"joins"`
+	TotalSeconds  int64     `json:"total_seconds"`
+	FirstJoinedAt time.Time `json:"first_joined_at"`
+	LastLeftAt    time.Time `json:"last_left_at,omitempty"`
+	Present       bool      `json:"present"`
+	Sessions      []Session `json:"sessions"`
+}
+
+// Attendance builds the attendance report of the room as of now. Sessions
+// that are still open are counted up to now.
+func (r *Room) Attendance(now time.Time) *AttendanceReport {
+	report := &AttendanceReport{
+		RoomID:      r.ID,
+		Status:      r.Status,
+		CreatedAt:   r.CreatedAt,
+		EndedAt:     r.EndedAt,
+		GeneratedAt: now,
+		Attendees:   []AttendanceRecord{},
+		Reactions:   map[string]int{},
+	}
+
+	for _, p := range r.Participants {
+		sessions := p.sessions()
+		if len(sessions) == 0 {
+			continue
+		}
+
+		record := AttendanceRecord{
+			UserID:        p.UserID,
+			Name:          p.Name,
+			Joins:         len(sessions),
+			FirstJoinedAt: sessions[0].JoinedAt,
+			Present:       p.LeftAt.IsZero(),
+			Sessions:      sessions,
+		}
+
+		var total time.Duration
+		for _, s := range sessions {
+			end := s.LeftAt
+			if end.IsZero() {
+				end = now
+			} else if end.After(record.LastLeftAt) {
+				record.LastLeftAt = end
+			}
+			if s.JoinedAt.Before(record.FirstJoinedAt) {
+				record.FirstJoinedAt = s.JoinedAt
+			}
+			total += end.Sub(s.JoinedAt)
+		}
+		record.TotalSeconds = int64(total / time.Second)
+
+		report.Attendees = append(report.Attendees, record)
+	}
+
+	sort.SliceStable(report.Attendees, func(i, j int) bool {
+		return report.Attendees[i].FirstJoinedAt.Before(report.Attendees[j].FirstJoinedAt)
+	})
+
+	return report
+}
@@ -0,0 +1,61 @@
+package room
+
+import "time"
+
+// RaisedHand is an entry in a room's speaking queue.
+type RaisedHand struct {
+	UserID   string    `json:"user_id" bson:"user_id"`
+	Name     string    `json:"name" bson:"name"`
+	RaisedAt time.Time `json:"raised_at" bson:"raised_at"`
+}
+
+// RaiseHand appends the participant to the end of the speaking queue.
+func (r *Room) RaiseHand(userID string) error {
+	p, ok := r.activeParticipant(userID)
+	if !ok {
+		return &RoomError{Message: "participant not found in room"}
+	}
+
+	for _, h := range r.HandQueue {
+		if h.UserID == userID {
+			return &RoomError{Message: "hand is already raised"}
+		}
+	}
+
+	r.HandQueue = append(r.HandQueue, RaisedHand{
+		UserID:   userID,
+		Name:     p.Name,
+		RaisedAt: time.Now(),
+	})
+	return nil
+}
+
+// LowerHand removes the user from the speaking queue.
+func (r *Room) LowerHand(userID string) error {
+	for i, h := range r.HandQueue {
+		if h.UserID == userID {
+			r.HandQueue = append(r.HandQueue[:i], r.HandQueue[i+1:]...)
+			return nil
+		}
+	}
+	return &RoomError{Message: "hand is not raised"}
+}
+
+// NextHand removes and returns the first entry of the speaking queue.
+func (r *Room) NextHand() (RaisedHand, error) {
+	if len(r.HandQueue) == 0 {
+		return RaisedHand{}, &RoomError{Message: "no hands are raised"}
+	}
+
+	next := r.HandQueue[0]
+	r.HandQueue = r.HandQueue[1:]
+	return next, nil
+}
+
+func (r *Room) activeParticipant(userID string) (Participant, bool) {
+	i, ok := r.activeParticipantIndex(userID)
+	if !ok {
+		return Participant{}, false
+	}
+	return r.Participants[i], true
+}
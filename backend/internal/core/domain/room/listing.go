@@ -0,0 +1,79 @@
+package room
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SortOrder string
+
+const (
+	SortNewest SortOrder = "newest"
+	SortOldest SortOrder = "oldest"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ListFilter selects the rooms returned by Repository.List. Zero fields do
+// not filter.
+type ListFilter struct {
+	// ViewerID restricts the result to rooms the viewer created or joined.
+	ViewerID      string
+	Status        RoomStatus
+	CreatedBy     string
+	ParticipantID string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Query is matched against the room title.
+	Query string
+	Sort  SortOrder
+	// ChannelID restricts the result to calls of the channel.
+	ChannelID string
+	// WithChat leaves out rooms whose chat was purged.
+	WithChat bool
+	// After continues a previous listing after the given room.
+	After *Cursor
+	Limit int
+}
+
+// Cursor identifies a position in a room listing. Rooms are ordered by
+// creation time, with the ID breaking ties.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, &RoomError{Message: "invalid cursor"}
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, &RoomError{Message: "invalid cursor"}
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, &RoomError{Message: "invalid cursor"}
+	}
+
+	return &Cursor{CreatedAt: time.Unix(0, n).UTC(), ID: id}, nil
+}
+
+// RoomPage is one page of a room listing.
+type RoomPage struct {
+	Rooms      []*Room `json:"rooms"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
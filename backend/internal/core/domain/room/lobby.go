@@ -0,0 +1,71 @@
+package room
+
+import "time"
+
+// LobbyEntry is someone waiting for a host to let them into a room that has
+// the lobby enabled.
+type LobbyEntry struct {
+	UserID      string    `json:"user_id" bson:"user_id"`
+	Name        string    `json:"name" bson:"name"`
+	Avatar      string    `json:"avatar" bson:"avatar"`
+	RequestedAt time.Time `json:"requested_at" bson:"requested_at"`
+}
+
+// MustWait reports whether the user has to wait in the lobby before
+// joining. The creator and anyone already admitted skip the lobby.
+func (r *Room) MustWait(userID string) bool {
+	return r.Settings.LobbyEnabled && !r.IsMember(userID)
+}
+
+// WaitInLobby adds the user to the lobby. Asking again keeps their place
+// and only refreshes their name and avatar.
+func (r *Room) WaitInLobby(userID, name, avatar string) {
+	for i, e := range r.Lobby {
+		if e.UserID == userID {
+			r.Lobby[i].Name = name
+			r.Lobby[i].Avatar = avatar
+			return
+		}
+	}
+
+	r.Lobby = append(r.Lobby, LobbyEntry{
+		UserID:      userID,
+		Name:        name,
+		Avatar:      avatar,
+		RequestedAt: time.Now(),
+	})
+}
+
+// Admit moves the user from the lobby into the room.
+func (r *Room) Admit(userID string) error {
+	for _, e := range r.Lobby {
+		if e.UserID != userID {
+			continue
+		}
+
+		if err := r.AddParticipant(e.UserID, e.Name, e.Avatar); err != nil {
+			return err
+		}
+		r.takeFromLobby(userID)
+		return nil
+	}
+	return &RoomError{Message: "user is not waiting in the lobby"}
+}
+
+// Deny removes the user from the lobby without letting them in.
+func (r *Room) Deny(userID string) error {
+	if _, ok := r.takeFromLobby(userID); !ok {
+		return &RoomError{Message: "user is not waiting in the lobby"}
+	}
+	return nil
+}
+
+func (r *Room) takeFromLobby(userID string) (LobbyEntry, bool) {
+	for i, e := range r.Lobby {
+		if e.UserID == userID {
+			r.Lobby = append(r.Lobby[:i], r.Lobby[i+1:]...)
+			return e, true
+		}
+	}
+	return LobbyEntry{}, false
+}
@@ -0,0 +1,91 @@
+package room
+
+// MuteParticipant records that a host muted the participant. With
+// blockUnmute the participant cannot unmute until a host allows it.
+func (r *Room) MuteParticipant(userID string, blockUnmute bool) error {
+	i, ok := r.activeParticipantIndex(userID)
+	if !ok {
+		return &RoomError{Message: "participant not found in room"}
+	}
+	if r.IsHost(userID) {
+		return &RoomError{Message: "hosts cannot be muted"}
+	}
+
+	r.Participants[i].MutedByHost = true
+	if blockUnmute {
+		r.Participants[i].UnmuteBlocked = true
+	}
+	return nil
+}
+
+// MuteAll mutes every active participant except hosts and returns the
+// IDs of the muted participants.
+func (r *Room) MuteAll(blockUnmute bool) []string {
+	muted := []string{}
+	for i, p := range r.Participants {
+		if !p.LeftAt.IsZero() || r.IsHost(p.UserID) {
+			continue
+		}
+		r.Participants[i].MutedByHost = true
+		if blockUnmute {
+			r.Participants[i].UnmuteBlocked = true
+		}
+		muted = append(muted, p.UserID)
+	}
+	return muted
+}
+
+// SetUnmuteBlocked blocks or allows unmuting for the participant.
+func (r *Room) SetUnmuteBlocked(userID string, blocked bool) error {
+	i, ok := r.activeParticipantIndex(userID)
+	if !ok {
+		return &RoomError{Message: "participant not found in room"}
+	}
+
+	r.Participants[i].UnmuteBlocked = blocked
+	return nil
+}
+
+// Unmute clears the host mute of a participant unmuting themselves.
+func (r *Room) Unmute(userID string) error {
+	i, ok := r.activeParticipantIndex(userID)
+	if !ok {
+		return &RoomError{Message: "participant not found in room"}
+	}
+	if r.Participants[i].UnmuteBlocked {
+		return &RoomError{Message: "a host has blocked unmuting"}
+	}
+
+	r.Participants[i].MutedByHost = false
+	return nil
+}
+
+// SetChatMuted stops or, with muted set to false, allows the participant
+// sending chat messages.
+func (r *Room) SetChatMuted(userID string, muted bool) error {
+	i, ok := r.activeParticipantIndex(userID)
+	if !ok {
+		return &RoomError{Message: "participant not found in room"}
+	}
+	if muted && r.IsHost(userID) {
+		return &RoomError{Message: "hosts cannot be muted"}
+	}
+
+	r.Participants[i].ChatMuted = muted
+	return nil
+}
+
+// IsChatMuted reports whether a host muted the user's chat.
+func (r *Room) IsChatMuted(userID string) bool {
+	p, ok := r.activeParticipant(userID)
+	return ok && p.ChatMuted
+}
+
+func (r *Room) activeParticipantIndex(userID string) (int, bool) {
+	for i, p := range r.Participants {
+		if p.UserID == userID && p.LeftAt.IsZero() {
+			return i, true
+		}
+	}
+	return -1, false
+}
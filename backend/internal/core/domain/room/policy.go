@@ -0,0 +1,71 @@
+package room
+
+import "time"
+
+// Policy holds the server-wide limits applied to rooms.
+type Policy struct {
+	MaxCapacity int
+	// MaxDuration caps how long a meeting may run. Zero means unlimited.
+	MaxDuration time.Duration
+	// MaxExtensions is how many times a host may extend a time-limited
+	// meeting. Zero disables extending.
+	MaxExtensions int
+	// MaxExtension caps the length of a single extension.
+	MaxExtension time.Duration
+	// MaxSpotlight is how many participants can be spotlighted at once.
+	MaxSpotlight int
+	// LegalHoldAdmins are the users who may place rooms on legal hold.
+	LegalHoldAdmins []string
+}
+
+// isLegalHoldAdmin reports whether the user may change legal holds.
+func (p Policy) isLegalHoldAdmin(userID string) bool {
+	for _, id := range p.LegalHoldAdmins {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultPolicy is the policy used for the MVP: ten participants, three of
+// them in the spotlight, and no time limit.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxCapacity:  10,
+		MaxSpotlight: 3,
+	}
+}
+
+// CreateOptions are the host's choices when creating a room.
+type CreateOptions struct {
+	Title string
+	// MaxCapacity lowers the room capacity below Policy.MaxCapacity. Zero
+	// means the policy capacity applies.
+	MaxCapacity int
+	Settings    Settings
+	// MaxDuration requests a time limit for the meeting. It is capped by
+	// Policy.MaxDuration, and zero means the policy limit applies.
+	MaxDuration time.Duration
+	// ChannelID links the room to the channel it was started from.
+	ChannelID string
+}
+
+// duration returns the time limit of a new meeting, or zero for none.
+func (p Policy) duration(requested time.Duration) time.Duration {
+	if requested <= 0 {
+		return p.MaxDuration
+	}
+	if p.MaxDuration > 0 && requested > p.MaxDuration {
+		return p.MaxDuration
+	}
+	return requested
+}
+
+// capacity returns the capacity of a new room.
+func (p Policy) capacity(requested int) int {
+	if requested <= 0 || requested > p.MaxCapacity {
+		return p.MaxCapacity
+	}
+	return requested
+}
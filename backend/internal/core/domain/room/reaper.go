@@ -0,0 +1,184 @@
+package room
+
+import (
+	"context"
+	"time"
+
+	"github.com/meet-clone/backend/internal/pkg/logger"
+)
+
+// Presence reports which users hold a live real-time connection to a room.
+type Presence interface {
+	IsConnected(roomID, userID string) bool
+	// LastSeen returns when the user last had a live connection to the
+	// room, and false if they never connected.
+	LastSeen(roomID, userID string) (time.Time, bool)
+	// Forget drops everything known about the room.
+	Forget(roomID string)
+	// Prune drops the last seen times of users who disconnected before
+	// the given time.
+	Prune(before time.Time)
+}
+
+// EventPublisher delivers room events to the connected clients of a room.
+type EventPublisher interface {
+	Publish(roomID, eventType string, payload interface{})
+}
+
+// SessionTerminator tears down the media session backing a room.
+type SessionTerminator interface {
+	DeleteSession(sessionID string) error
+}
+
+type ReaperConfig struct {
+	// Interval is how often active rooms are checked.
+	Interval time.Duration
+	// GracePeriod is how long a participant may be disconnected before
+	// they are marked as left.
+	GracePeriod time.Duration
+	// IdleTimeout is how long a room may go without any live connection
+	// before it is ended.
+	IdleTimeout time.Duration
+}
+
+// Reaper ends rooms and removes participants that were abandoned without
+// calling LeaveRoom, for example because the browser crashed.
+type Reaper struct {
+	finisher
+	repo    Repository
+	service Service
+	config  ReaperConfig
+}
+
+func NewReaper(repo Repository, service Service, presence Presence, events EventPublisher, sessions SessionTerminator, config ReaperConfig) *Reaper {
+	return &Reaper{
+		finisher: finisher{
+			presence: presence,
+			events:   events,
+			sessions: sessions,
+		},
+		repo:    repo,
+		service: service,
+		config:  config,
+	}
+}
+
+// Run reaps rooms every Interval until ctx is cancelled.
+func (r *Reaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Reap(ctx)
+		}
+	}
+}
+
+// Reap checks every active room once.
+func (r *Reaper) Reap(ctx context.Context) {
+	rooms, err := listActiveRooms(ctx, r.repo)
+	if err != nil {
+		logger.Error.Printf("Reaper failed to list active rooms: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, room := range rooms {
+		r.reapRoom(ctx, room, now)
+	}
+
+	// Participants gone for longer than the grace period were removed
+	// above, so their last seen times are no longer needed
+	r.presence.Prune(now.Add(-r.config.GracePeriod))
+}
+
+func (r *Reaper) reapRoom(ctx context.Context, room *Room, now time.Time) {
+	live := false
+	lastActivity := room.CreatedAt
+
+	for _, p := range room.GetActiveParticipants() {
+		if r.presence.IsConnected(room.ID, p.UserID) {
+			live = true
+			continue
+		}
+
+		seen, ok := r.presence.LastSeen(room.ID, p.UserID)
+		if !ok || seen.Before(p.JoinedAt) {
+			seen = p.JoinedAt
+		}
+		if seen.After(lastActivity) {
+			lastActivity = seen
+		}
+
+		if now.Sub(seen) < r.config.GracePeriod {
+			continue
+		}
+
+		updated, err := r.service.LeaveRoom(ctx, room.ID, p.UserID)
+		if err != nil {
+			logger.Error.Printf("Reaper failed to remove participant %s from room %s: %v", p.UserID, room.ID, err)
+			continue
+		}
+		logger.Info.Printf("Reaper removed disconnected participant %s from room %s", p.UserID, room.ID)
+		r.events.Publish(room.ID, "participant_left", map[string]string{"user_id": p.UserID})
+
+		if !updated.IsActive() {
+			r.finish(updated, "abandoned")
+			return
+		}
+	}
+
+	if live || now.Sub(lastActivity) < r.config.IdleTimeout {
+		return
+	}
+
+	ended, err := r.service.CloseRoom(ctx, room.ID)
+	if err != nil {
+		logger.Error.Printf("Reaper failed to end idle room %s: %v", room.ID, err)
+		return
+	}
+	logger.Info.Printf("Reaper ended idle room %s", room.ID)
+	r.finish(ended, "idle")
+}
+
+// activeRoomsBatchSize is the page size used to walk the active rooms.
+const activeRoomsBatchSize = 100
+
+// listActiveRooms loads every active room. Rooms are collected up front
+// because ending one while paging would shift the remaining pages.
+func listActiveRooms(ctx context.Context, repo Repository) ([]*Room, error) {
+	var rooms []*Room
+	for offset := 0; ; offset += activeRoomsBatchSize {
+		batch, err := repo.FindActiveRooms(ctx, activeRoomsBatchSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, batch...)
+		if len(batch) < activeRoomsBatchSize {
+			return rooms, nil
+		}
+	}
+}
+
+// finisher releases what a room ended by a background job still holds.
+type finisher struct {
+	presence Presence
+	events   EventPublisher
+	sessions SessionTerminator
+}
+
+func (f *finisher) finish(room *Room, reason string) {
+	f.events.Publish(room.ID, "room_ended", map[string]string{"reason": reason})
+	f.presence.Forget(room.ID)
+
+	if room.CloudflareSessionID == "" {
+		return
+	}
+	if err := f.sessions.DeleteSession(room.CloudflareSessionID); err != nil {
+		logger.Error.Printf("Failed to delete Cloudflare session %s of room %s: %v", room.CloudflareSessionID, room.ID, err)
+	}
+}
@@ -6,7 +6,13 @@ type Repository interface {
 	Create(ctx context.Context, room *Room) error
 	FindByID(ctx context.Context, id string) (*Room, error)
 	FindByCreator(ctx context.Context, createdBy string) ([]*Room, error)
+	// Update saves the room if its Version still matches the stored one and
+	// increments the Version. It returns ErrVersionConflict otherwise.
 	Update(ctx context.Context, room *Room) error
 	Delete(ctx context.Context, id string) error
 	FindActiveRooms(ctx context.Context, limit, offset int) ([]*Room, error)
+	List(ctx context.Context, filter ListFilter) ([]*Room, error)
+	// AddReactions adds to the stored reaction counts of the room.
+	AddReactions(ctx context.Context, roomID string, counts map[string]int) error
+	FindReactions(ctx context.Context, roomID string) (map[string]int, error)
 }
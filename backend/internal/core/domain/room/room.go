@@ -1,6 +1,7 @@
 package room
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,23 +14,153 @@ const (
 	RoomStatusEnded  RoomStatus = "ended"
 )
 
+type ParticipantRole string
+
+const (
+	RoleHost        ParticipantRole = "host"
+	RoleParticipant ParticipantRole = "participant"
+)
+
 type Participant struct {
-	UserID   string    `json:"user_id" bson:"user_id"`
-	Name     string    `json:"name" bson:"name"`
-	Avatar   string    `json:"avatar" bson:"avatar"`
+	UserID   string          `json:"user_id" bson:"user_id"`
+	Name     string          `json:"name" bson:"name"`
+	Avatar   string          `json:"avatar" bson:"avatar"`
+	Role     ParticipantRole `json:"role" bson:"role"`
+	JoinedAt time.Time       `json:"joined_at" bson:"joined_at"`
+	LeftAt   time.Time       `json:"left_at,omitempty" bson:"left_at,omitempty"`
+	Sessions []Session       `json:"sessions,omitempty" bson:"sessions,omitempty"`
+	// MutedByHost and UnmuteBlocked survive leaving and rejoining, so a
+	// reconnecting client comes back muted.
+	MutedByHost   bool `json:"muted_by_host" bson:"muted_by_host"`
+	UnmuteBlocked bool `json:"unmute_blocked" bson:"unmute_blocked"`
+	// ChatMuted stops the participant from sending chat messages.
+	ChatMuted bool `json:"chat_muted" bson:"chat_muted"`
+}
+
+// Session is a single join/leave interval of a participant.
+type Session struct {
 	JoinedAt time.Time `json:"joined_at" bson:"joined_at"`
 	LeftAt   time.Time `json:"left_at,omitempty" bson:"left_at,omitempty"`
 }
 
+// Settings are the host-configurable behaviours of a room.
+type Settings struct {
+	// LobbyEnabled makes participants wait in the lobby until admitted.
+	LobbyEnabled bool `json:"lobby_enabled" bson:"lobby_enabled"`
+	ChatDisabled bool `json:"chat_disabled" bson:"chat_disabled"`
+	// PrivateChatDisabled stops participants from messaging each other
+	// privately while room chat stays available.
+	PrivateChatDisabled bool `json:"private_chat_disabled" bson:"private_chat_disabled"`
+	// SlowModeSeconds is how long participants other than hosts wait
+	// between chat messages. Zero turns slow mode off.
+	SlowModeSeconds int `json:"slow_mode_seconds" bson:"slow_mode_seconds"`
+	// AllowedReactions limits the emoji reactions participants can send.
+	// Empty means DefaultReactions.
+	AllowedReactions []string `json:"allowed_reactions,omitempty" bson:"allowed_reactions,omitempty"`
+	// ChatRetentionDays deletes the chat that many days after the meeting
+	// ends. Zero leaves it to the organization or server retention.
+	ChatRetentionDays int `json:"chat_retention_days,omitempty" bson:"chat_retention_days,omitempty"`
+}
+
+// SettingsUpdate changes some settings of a room. Nil fields are left as
+// they are.
+type SettingsUpdate struct {
+	LobbyEnabled        *bool
+	ChatDisabled        *bool
+	PrivateChatDisabled *bool
+	SlowModeSeconds     *int
+	AllowedReactions    []string
+	ChatRetentionDays   *int
+}
+
+// Apply returns the settings with the update applied.
+func (u SettingsUpdate) Apply(s Settings) Settings {
+	if u.LobbyEnabled != nil {
+		s.LobbyEnabled = *u.LobbyEnabled
+	}
+	if u.ChatDisabled != nil {
+		s.ChatDisabled = *u.ChatDisabled
+	}
+	if u.PrivateChatDisabled != nil {
+		s.PrivateChatDisabled = *u.PrivateChatDisabled
+	}
+	if u.SlowModeSeconds != nil {
+		s.SlowModeSeconds = *u.SlowModeSeconds
+	}
+	if u.AllowedReactions != nil {
+		s.AllowedReactions = append([]string(nil), u.AllowedReactions...)
+	}
+	if u.ChatRetentionDays != nil {
+		s.ChatRetentionDays = *u.ChatRetentionDays
+	}
+	return s
+}
+
+// DefaultReactions are the reactions allowed in rooms that do not choose
+// their own.
+var DefaultReactions = []string{"👍", "👏", "😂", "❤️"}
+
+// maxReactionLength bounds a reaction so it stays a single emoji sequence.
+const maxReactionLength = 32
+
+// maxSlowModeSeconds bounds the slow mode interval to an hour.
+const maxSlowModeSeconds = 3600
+
+// maxChatRetentionDays bounds the room chat retention to ten years.
+const maxChatRetentionDays = 3650
+
+func (s Settings) Validate() error {
+	if s.SlowModeSeconds < 0 || s.SlowModeSeconds > maxSlowModeSeconds {
+		return &RoomError{Message: "slow mode must be between 0 and 3600 seconds"}
+	}
+	if s.ChatRetentionDays < 0 || s.ChatRetentionDays > maxChatRetentionDays {
+		return &RoomError{Message: "chat retention must be between 0 and 3650 days"}
+	}
+	for _, emoji := range s.AllowedReactions {
+		if emoji == "" || len(emoji) > maxReactionLength || strings.ContainsAny(emoji, ".$") {
+			return &RoomError{Message: "invalid reaction: " + emoji}
+		}
+	}
+	return nil
+}
+
+// AllowsReaction reports whether the emoji may be sent as a reaction.
+func (s Settings) AllowsReaction(emoji string) bool {
+	allowed := s.AllowedReactions
+	if len(allowed) == 0 {
+		allowed = DefaultReactions
+	}
+	for _, a := range allowed {
+		if a == emoji {
+			return true
+		}
+	}
+	return false
+}
+
 type Room struct {
 	ID                  string        `json:"id" bson:"_id"`
+	Title               string        `json:"title" bson:"title"`
 	CreatedBy           string        `json:"created_by" bson:"created_by"`
 	CloudflareSessionID string        `json:"cloudflare_session_id,omitempty" bson:"cloudflare_session_id,omitempty"`
 	Status              RoomStatus    `json:"status" bson:"status"`
 	Participants        []Participant `json:"participants" bson:"participants"`
 	MaxCapacity         int           `json:"max_capacity" bson:"max_capacity"`
+	Settings            Settings      `json:"settings" bson:"settings"`
+	HandQueue           []RaisedHand  `json:"hand_queue" bson:"hand_queue"`
+	Spotlight           []string      `json:"spotlight" bson:"spotlight"`
+	Lobby               []LobbyEntry  `json:"lobby" bson:"lobby"`
 	CreatedAt           time.Time     `json:"created_at" bson:"created_at"`
 	EndedAt             time.Time     `json:"ended_at,omitempty" bson:"ended_at,omitempty"`
+	ExpiresAt           time.Time     `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	Extensions          int           `json:"extensions" bson:"extensions"`
+	// ChannelID is the channel the call was started from, if any.
+	ChannelID string `json:"channel_id,omitempty" bson:"channel_id,omitempty"`
+	// LegalHold keeps the chat of the room past its retention period.
+	LegalHold bool `json:"legal_hold" bson:"legal_hold"`
+	// ChatPurgedAt is when the retention job deleted the chat.
+	ChatPurgedAt time.Time `json:"chat_purged_at,omitempty" bson:"chat_purged_at,omitempty"`
+	Version      int64     `json:"-" bson:"version"`
 }
 
 func NewRoom(createdBy string, maxCapacity int) *Room {
@@ -38,13 +169,16 @@ func NewRoom(createdBy string, maxCapacity int) *Room {
 		CreatedBy:    createdBy,
 		Status:       RoomStatusActive,
 		Participants: []Participant{},
+		HandQueue:    []RaisedHand{},
+		Spotlight:    []string{},
+		Lobby:        []LobbyEntry{},
 		MaxCapacity:  maxCapacity,
 		CreatedAt:    time.Now(),
 	}
 }
 
 func (r *Room) AddParticipant(userID, name, avatar string) error {
-	if len(r.Participants) >= r.MaxCapacity {
+	if len(r.GetActiveParticipants()) >= r.MaxCapacity {
 		return &RoomError{Message: "room is at maximum capacity"}
 	}
 
@@ -55,11 +189,32 @@ func (r *Room) AddParticipant(userID, name, avatar string) error {
 		}
 	}
 
+	now := time.Now()
+
+	// Rejoining reuses the participant entry and records a new session
+	for i, p := range r.Participants {
+		if p.UserID == userID {
+			r.Participants[i].Name = name
+			r.Participants[i].Avatar = avatar
+			r.Participants[i].JoinedAt = now
+			r.Participants[i].LeftAt = time.Time{}
+			r.Participants[i].Sessions = append(p.sessions(), Session{JoinedAt: now})
+			return nil
+		}
+	}
+
+	role := RoleParticipant
+	if userID == r.CreatedBy {
+		role = RoleHost
+	}
+
 	r.Participants = append(r.Participants, Participant{
 		UserID:   userID,
 		Name:     name,
 		Avatar:   avatar,
-		JoinedAt: time.Now(),
+		Role:     role,
+		JoinedAt: now,
+		Sessions: []Session{{JoinedAt: now}},
 	})
 
 	return nil
@@ -68,13 +223,35 @@ func (r *Room) AddParticipant(userID, name, avatar string) error {
 func (r *Room) RemoveParticipant(userID string) error {
 	for i, p := range r.Participants {
 		if p.UserID == userID && p.LeftAt.IsZero() {
-			r.Participants[i].LeftAt = time.Now()
+			r.Participants[i].leave(time.Now())
+			r.LowerHand(userID)
+			r.RemoveSpotlight(userID)
 			return nil
 		}
 	}
 	return &RoomError{Message: "participant not found in room"}
 }
 
+// sessions returns the participant's join history. Participants stored
+// before sessions were tracked get a single session built from JoinedAt
+// and LeftAt.
+func (p Participant) sessions() []Session {
+	if len(p.Sessions) > 0 || p.JoinedAt.IsZero() {
+		return p.Sessions
+	}
+	return []Session{{JoinedAt: p.JoinedAt, LeftAt: p.LeftAt}}
+}
+
+// leave marks the participant as gone and closes their open session.
+func (p *Participant) leave(at time.Time) {
+	sessions := p.sessions()
+	if last := len(sessions) - 1; last >= 0 && sessions[last].LeftAt.IsZero() {
+		sessions[last].LeftAt = at
+	}
+	p.Sessions = sessions
+	p.LeftAt = at
+}
+
 func (r *Room) GetActiveParticipants() []Participant {
 	active := []Participant{}
 	for _, p := range r.Participants {
@@ -86,14 +263,64 @@ func (r *Room) GetActiveParticipants() []Participant {
 }
 
 func (r *Room) End() {
+	now := time.Now()
 	r.Status = RoomStatusEnded
-	r.EndedAt = time.Now()
+	r.EndedAt = now
+
+	r.HandQueue = []RaisedHand{}
+	r.ClearSpotlight()
+	r.Lobby = []LobbyEntry{}
+
+	// Close the sessions of anyone still in the room
+	for i, p := range r.Participants {
+		if p.LeftAt.IsZero() {
+			r.Participants[i].leave(now)
+		}
+	}
 }
 
 func (r *Room) IsActive() bool {
 	return r.Status == RoomStatusActive
 }
 
+// IsHost reports whether the user may manage the room. The creator is
+// always a host.
+func (r *Room) IsHost(userID string) bool {
+	if r.CreatedBy == userID {
+		return true
+	}
+	p, ok := r.activeParticipant(userID)
+	return ok && p.Role == RoleHost
+}
+
+// IsParticipant reports whether the user is currently in the room.
+func (r *Room) IsParticipant(userID string) bool {
+	_, ok := r.activeParticipant(userID)
+	return ok
+}
+
+// IsMember reports whether the user created the room or ever joined it.
+func (r *Room) IsMember(userID string) bool {
+	if r.CreatedBy == userID {
+		return true
+	}
+	for _, p := range r.Participants {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTimeLimit reports whether the room ends automatically at ExpiresAt.
+func (r *Room) HasTimeLimit() bool {
+	return !r.ExpiresAt.IsZero()
+}
+
+// ErrVersionConflict is returned by Repository.Update when the room was
+// modified by someone else since it was read.
+var ErrVersionConflict = &RoomError{Message: "room was modified concurrently"}
+
 type RoomError struct {
 	Message string
 }
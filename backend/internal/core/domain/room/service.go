@@ -2,33 +2,83 @@ package room
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/meet-clone/backend/internal/pkg/errors"
 )
 
 type Service interface {
-	CreateRoom(ctx context.Context, userID string) (*Room, error)
+	CreateRoom(ctx context.Context, userID string, opts CreateOptions) (*Room, error)
 	JoinRoom(ctx context.Context, roomID, userID, userName, avatar string) (*Room, error)
 	LeaveRoom(ctx context.Context, roomID, userID string) (*Room, error)
 	GetRoomDetails(ctx context.Context, roomID string) (*Room, error)
 	GetUserRooms(ctx context.Context, userID string) ([]*Room, error)
+	ListRooms(ctx context.Context, filter ListFilter) (*RoomPage, error)
 	EndRoom(ctx context.Context, roomID, userID string) error
 	GetActiveParticipants(ctx context.Context, roomID string) ([]Participant, error)
 	SetSessionID(ctx context.Context, roomID, sessionID string) error
+	GetAttendance(ctx context.Context, roomID, userID string) (*AttendanceReport, error)
+	CloseRoom(ctx context.Context, roomID string) (*Room, error)
+	CloseExpiredRoom(ctx context.Context, roomID string, now time.Time) (*Room, error)
+	ExtendRoom(ctx context.Context, roomID, userID string, by time.Duration) (*Room, error)
+	RaiseHand(ctx context.Context, roomID, userID string) (*Room, error)
+	LowerHand(ctx context.Context, roomID, userID, targetID string) (*Room, error)
+	CallNextHand(ctx context.Context, roomID, userID string) (*Room, *RaisedHand, error)
+	RecordReactions(ctx context.Context, roomID string, counts map[string]int) error
+	MuteParticipant(ctx context.Context, roomID, userID, targetID string, blockUnmute bool) (*Room, error)
+	MuteAll(ctx context.Context, roomID, userID string, blockUnmute bool) (*Room, []string, error)
+	SetUnmuteBlocked(ctx context.Context, roomID, userID, targetID string, blocked bool) (*Room, error)
+	Unmute(ctx context.Context, roomID, userID string) (*Room, error)
+	SetChatMuted(ctx context.Context, roomID, userID, targetID string, muted bool) (*Room, error)
+	AuthorizeModeration(ctx context.Context, roomID, userID, targetID string) error
+	AddSpotlight(ctx context.Context, roomID, userID, targetID string) (*Room, error)
+	RemoveSpotlight(ctx context.Context, roomID, userID, targetID string) (*Room, error)
+	ClearSpotlight(ctx context.Context, roomID, userID string) (*Room, error)
+	AdmitFromLobby(ctx context.Context, roomID, userID, targetID string) (*Room, error)
+	DenyFromLobby(ctx context.Context, roomID, userID, targetID string) (*Room, error)
+	UpdateSettings(ctx context.Context, roomID, userID string, update SettingsUpdate) (*Room, error)
+	// SetLegalHold places the room on legal hold or releases it. Only the
+	// legal hold admins of the policy can do this.
+	SetLegalHold(ctx context.Context, roomID, userID string, held bool) (*Room, error)
+	// MarkChatPurged records that the chat of an ended room was deleted.
+	MarkChatPurged(ctx context.Context, roomID string, at time.Time) (*Room, error)
 }
 
+// maxUpdateAttempts bounds how often a read-modify-write is retried when
+// another request updated the same room in between.
+const maxUpdateAttempts = 5
+
 type service struct {
-	repo Repository
+	repo   Repository
+	policy Policy
 }
 
-func NewService(repo Repository) Service {
+func NewService(repo Repository, policy Policy) Service {
 	return &service{
-		repo: repo,
+		repo:   repo,
+		policy: policy,
 	}
 }
 
-func (s *service) CreateRoom(ctx context.Context, userID string) (*Room, error) {
-	room := NewRoom(userID, 10) // Max 10 participants for MVP
+func (s *service) CreateRoom(ctx context.Context, userID string, opts CreateOptions) (*Room, error) {
+	if opts.MaxDuration < 0 {
+		return nil, errors.NewValidationError("max duration cannot be negative")
+	}
+	if opts.MaxCapacity < 0 {
+		return nil, errors.NewValidationError("max capacity cannot be negative")
+	}
+	if err := opts.Settings.Validate(); err != nil {
+		return nil, errors.NewValidationError(err.Error())
+	}
+
+	room := NewRoom(userID, s.policy.capacity(opts.MaxCapacity))
+	room.Title = strings.TrimSpace(opts.Title)
+	room.Settings = opts.Settings
+	room.ChannelID = opts.ChannelID
+	if d := s.policy.duration(opts.MaxDuration); d > 0 {
+		room.ExpiresAt = room.CreatedAt.Add(d)
+	}
 
 	if err := s.repo.Create(ctx, room); err != nil {
 		return nil, errors.NewInternalError("failed to create room", err)
@@ -37,47 +87,42 @@ func (s *service) CreateRoom(ctx context.Context, userID string) (*Room, error)
 	return room, nil
 }
 
+// JoinRoom adds the user to the room. When the room has the lobby enabled,
+// users who were never admitted are put in the lobby instead and wait for a
+// host; the returned room then still reports MustWait for them.
 func (s *service) JoinRoom(ctx context.Context, roomID, userID, userName, avatar string) (*Room, error) {
-	room, err := s.repo.FindByID(ctx, roomID)
-	if err != nil {
-		return nil, errors.NewNotFoundError("room not found")
-	}
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsActive() {
+			return errors.NewValidationError("room has ended")
+		}
 
-	if !room.IsActive() {
-		return nil, errors.NewValidationError("room has ended")
-	}
+		if room.MustWait(userID) {
+			room.WaitInLobby(userID, userName, avatar)
+			return nil
+		}
 
-	if err := room.AddParticipant(userID, userName, avatar); err != nil {
-		return nil, errors.NewValidationError(err.Error())
-	}
+		if err := room.AddParticipant(userID, userName, avatar); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		room.takeFromLobby(userID)
 
-	if err := s.repo.Update(ctx, room); err != nil {
-		return nil, errors.NewInternalError("failed to update room", err)
-	}
-
-	return room, nil
+		return nil
+	})
 }
 
 func (s *service) LeaveRoom(ctx context.Context, roomID, userID string) (*Room, error) {
-	room, err := s.repo.FindByID(ctx, roomID)
-	if err != nil {
-		return nil, errors.NewNotFoundError("room not found")
-	}
+	return s.update(ctx, roomID, func(room *Room) error {
+		if err := room.RemoveParticipant(userID); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
 
-	if err := room.RemoveParticipant(userID); err != nil {
-		return nil, errors.NewValidationError(err.Error())
-	}
+		// End room if no active participants
+		if len(room.GetActiveParticipants()) == 0 {
+			room.End()
+		}
 
-	// End room if no active participants
-	if len(room.GetActiveParticipants()) == 0 {
-		room.End()
-	}
-
-	if err := s.repo.Update(ctx, room); err != nil {
-		return nil, errors.NewInternalError("failed to update room", err)
-	}
-
-	return room, nil
+		return nil
+	})
 }
 
 func (s *service) GetRoomDetails(ctx context.Context, roomID string) (*Room, error) {
@@ -98,23 +143,52 @@ func (s *service) GetUserRooms(ctx context.Context, userID string) ([]*Room, err
 	return rooms, nil
 }
 
-func (s *service) EndRoom(ctx context.Context, roomID, userID string) error {
-	room, err := s.repo.FindByID(ctx, roomID)
+func (s *service) ListRooms(ctx context.Context, filter ListFilter) (*RoomPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Sort == "" {
+		filter.Sort = SortNewest
+	}
+	if filter.Sort != SortNewest && filter.Sort != SortOldest {
+		return nil, errors.NewValidationError("sort must be newest or oldest")
+	}
+	if filter.Status != "" && filter.Status != RoomStatusActive && filter.Status != RoomStatusEnded {
+		return nil, errors.NewValidationError("status must be active or ended")
+	}
+
+	// Fetch one extra room to learn whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	rooms, err := s.repo.List(ctx, filter)
 	if err != nil {
-		return errors.NewNotFoundError("room not found")
+		return nil, errors.NewInternalError("failed to list rooms", err)
 	}
 
-	if room.CreatedBy != userID {
-		return errors.NewForbiddenError("only the room creator can end the room")
+	page := &RoomPage{Rooms: rooms}
+	if len(rooms) > limit {
+		page.Rooms = rooms[:limit]
+		last := page.Rooms[limit-1]
+		page.NextCursor = Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
 	}
 
-	room.End()
+	return page, nil
+}
 
-	if err := s.repo.Update(ctx, room); err != nil {
-		return errors.NewInternalError("failed to end room", err)
-	}
+func (s *service) EndRoom(ctx context.Context, roomID, userID string) error {
+	_, err := s.update(ctx, roomID, func(room *Room) error {
+		if room.CreatedBy != userID {
+			return errors.NewForbiddenError("only the room creator can end the room")
+		}
 
-	return nil
+		room.End()
+		return nil
+	})
+	return err
 }
 
 func (s *service) GetActiveParticipants(ctx context.Context, roomID string) ([]Participant, error) {
@@ -125,3 +199,79 @@ func (s *service) GetActiveParticipants(ctx context.Context, roomID string) ([]P
 
 	return room.GetActiveParticipants(), nil
 }
+
+func (s *service) GetAttendance(ctx context.Context, roomID, userID string) (*AttendanceReport, error) {
+	room, err := s.repo.FindByID(ctx, roomID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("room not found")
+	}
+
+	if room.CreatedBy != userID {
+		return nil, errors.NewForbiddenError("only the room creator can view attendance")
+	}
+
+	report := room.Attendance(time.Now())
+
+	reactions, err := s.repo.FindReactions(ctx, roomID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to get reactions", err)
+	}
+	report.Reactions = reactions
+
+	return report, nil
+}
+
+func (s *service) ExtendRoom(ctx context.Context, roomID, userID string, by time.Duration) (*Room, error) {
+	if by <= 0 {
+		return nil, errors.NewValidationError("extension must be positive")
+	}
+	if s.policy.MaxExtension > 0 && by > s.policy.MaxExtension {
+		return nil, errors.NewValidationError("extension exceeds the allowed maximum of " + s.policy.MaxExtension.String())
+	}
+
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can extend the meeting")
+		}
+		if !room.IsActive() {
+			return errors.NewValidationError("room has ended")
+		}
+		if !room.HasTimeLimit() {
+			return errors.NewValidationError("meeting has no time limit")
+		}
+		if room.Extensions >= s.policy.MaxExtensions {
+			return errors.NewForbiddenError("meeting cannot be extended any further")
+		}
+
+		room.ExpiresAt = room.ExpiresAt.Add(by)
+		room.Extensions++
+		return nil
+	})
+}
+
+// update loads the room, applies mutate and saves it, retrying from a fresh
+// read whenever the save loses a race against a concurrent update.
+func (s *service) update(ctx context.Context, roomID string, mutate func(room *Room) error) (*Room, error) {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		room, err := s.repo.FindByID(ctx, roomID)
+		if err != nil {
+			return nil, errors.NewNotFoundError("room not found")
+		}
+
+		if err := mutate(room); err != nil {
+			return nil, err
+		}
+
+		err = s.repo.Update(ctx, room)
+		if err == ErrVersionConflict {
+			continue
+		}
+		if err != nil {
+			return nil, errors.NewInternalError("failed to update room", err)
+		}
+
+		return room, nil
+	}
+
+	return nil, errors.NewConflictError("room is being updated by other requests, try again")
+}
@@ -2,21 +2,283 @@ package room
 
 import (
 	"context"
+	"time"
 
 	"github.com/meet-clone/backend/internal/pkg/errors"
 )
 
 func (s *service) SetSessionID(ctx context.Context, roomID, sessionID string) error {
+	_, err := s.update(ctx, roomID, func(room *Room) error {
+		room.CloudflareSessionID = sessionID
+		return nil
+	})
+	return err
+}
+
+// CloseRoom ends the room on behalf of the system, without the creator
+// check EndRoom applies.
+func (s *service) CloseRoom(ctx context.Context, roomID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsActive() {
+			return errors.NewValidationError("room has ended")
+		}
+
+		room.End()
+		return nil
+	})
+}
+
+// CloseExpiredRoom ends a time-limited room whose limit had passed at now.
+// A room that was extended in the meantime is returned unchanged and stays
+// active.
+func (s *service) CloseExpiredRoom(ctx context.Context, roomID string, now time.Time) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsActive() {
+			return errors.NewValidationError("room has ended")
+		}
+		if !room.HasTimeLimit() || room.ExpiresAt.After(now) {
+			return nil
+		}
+
+		room.End()
+		return nil
+	})
+}
+
+func (s *service) RaiseHand(ctx context.Context, roomID, userID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if err := room.RaiseHand(userID); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		return nil
+	})
+}
+
+// LowerHand lowers the target's hand. Participants may lower their own hand
+// and hosts may lower anyone's.
+func (s *service) LowerHand(ctx context.Context, roomID, userID, targetID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if userID != targetID && !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can lower another participant's hand")
+		}
+		if err := room.LowerHand(targetID); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		return nil
+	})
+}
+
+// CallNextHand removes the first participant from the speaking queue so the
+// host can give them the floor.
+func (s *service) CallNextHand(ctx context.Context, roomID, userID string) (*Room, *RaisedHand, error) {
+	var called RaisedHand
+	room, err := s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can call on the next participant")
+		}
+		next, err := room.NextHand()
+		if err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		called = next
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return room, &called, nil
+}
+
+// RecordReactions adds aggregated reaction counts to the meeting report.
+func (s *service) RecordReactions(ctx context.Context, roomID string, counts map[string]int) error {
+	if err := s.repo.AddReactions(ctx, roomID, counts); err != nil {
+		return errors.NewInternalError("failed to record reactions", err)
+	}
+	return nil
+}
+
+// MuteParticipant mutes a participant on behalf of a host.
+func (s *service) MuteParticipant(ctx context.Context, roomID, userID, targetID string, blockUnmute bool) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can mute participants")
+		}
+		if err := room.MuteParticipant(targetID, blockUnmute); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		return nil
+	})
+}
+
+// MuteAll mutes everyone except hosts and returns who was muted.
+func (s *service) MuteAll(ctx context.Context, roomID, userID string, blockUnmute bool) (*Room, []string, error) {
+	var muted []string
+	room, err := s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can mute participants")
+		}
+		muted = room.MuteAll(blockUnmute)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return room, muted, nil
+}
+
+func (s *service) SetUnmuteBlocked(ctx context.Context, roomID, userID, targetID string, blocked bool) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can block unmuting")
+		}
+		if err := room.SetUnmuteBlocked(targetID, blocked); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		return nil
+	})
+}
+
+func (s *service) SetChatMuted(ctx context.Context, roomID, userID, targetID string, muted bool) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can mute the chat of participants")
+		}
+		if err := room.SetChatMuted(targetID, muted); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		return nil
+	})
+}
+
+// Unmute records that a participant unmuted themselves, which fails while
+// a host blocks unmuting.
+func (s *service) Unmute(ctx context.Context, roomID, userID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if err := room.Unmute(userID); err != nil {
+			return errors.NewForbiddenError(err.Error())
+		}
+		return nil
+	})
+}
+
+// AuthorizeModeration checks that the user is a host who may act on the
+// target, for moderation requests that leave no state behind.
+func (s *service) AuthorizeModeration(ctx context.Context, roomID, userID, targetID string) error {
 	room, err := s.repo.FindByID(ctx, roomID)
 	if err != nil {
 		return errors.NewNotFoundError("room not found")
 	}
 
-	room.CloudflareSessionID = sessionID
-
-	if err := s.repo.Update(ctx, room); err != nil {
-		return errors.NewInternalError("failed to update room", err)
+	if !room.IsHost(userID) {
+		return errors.NewForbiddenError("only a host can moderate participants")
+	}
+	if _, ok := room.activeParticipant(targetID); !ok {
+		return errors.NewValidationError("participant not found in room")
 	}
 
 	return nil
 }
+
+func (s *service) AddSpotlight(ctx context.Context, roomID, userID, targetID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can spotlight participants")
+		}
+		if err := room.AddSpotlight(targetID, s.policy.MaxSpotlight); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		return nil
+	})
+}
+
+func (s *service) RemoveSpotlight(ctx context.Context, roomID, userID, targetID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can change the spotlight")
+		}
+		if !room.RemoveSpotlight(targetID) {
+			return errors.NewValidationError("participant is not spotlighted")
+		}
+		return nil
+	})
+}
+
+func (s *service) ClearSpotlight(ctx context.Context, roomID, userID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can change the spotlight")
+		}
+		room.ClearSpotlight()
+		return nil
+	})
+}
+
+// AdmitFromLobby lets a user waiting in the lobby into the room. Only hosts
+// can admit.
+func (s *service) AdmitFromLobby(ctx context.Context, roomID, userID, targetID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can admit participants")
+		}
+		if err := room.Admit(targetID); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		return nil
+	})
+}
+
+// DenyFromLobby turns away a user waiting in the lobby. Only hosts can deny.
+func (s *service) DenyFromLobby(ctx context.Context, roomID, userID, targetID string) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can deny participants")
+		}
+		if err := room.Deny(targetID); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		return nil
+	})
+}
+
+// UpdateSettings changes the settings of an active room. Only hosts can
+// change them.
+func (s *service) UpdateSettings(ctx context.Context, roomID, userID string, update SettingsUpdate) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if !room.IsActive() {
+			return errors.NewValidationError("room has ended")
+		}
+		if !room.IsHost(userID) {
+			return errors.NewForbiddenError("only a host can change room settings")
+		}
+
+		settings := update.Apply(room.Settings)
+		if err := settings.Validate(); err != nil {
+			return errors.NewValidationError(err.Error())
+		}
+		room.Settings = settings
+		return nil
+	})
+}
+
+func (s *service) SetLegalHold(ctx context.Context, roomID, userID string, held bool) (*Room, error) {
+	if !s.policy.isLegalHoldAdmin(userID) {
+		return nil, errors.NewForbiddenError("only legal hold admins can change legal holds")
+	}
+	return s.update(ctx, roomID, func(room *Room) error {
+		room.LegalHold = held
+		return nil
+	})
+}
+
+func (s *service) MarkChatPurged(ctx context.Context, roomID string, at time.Time) (*Room, error) {
+	return s.update(ctx, roomID, func(room *Room) error {
+		if room.IsActive() {
+			return errors.NewValidationError("room is still active")
+		}
+		if room.LegalHold {
+			return errors.NewValidationError("room is on legal hold")
+		}
+		room.ChatPurgedAt = at
+		return nil
+	})
+}
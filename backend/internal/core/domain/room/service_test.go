@@ -0,0 +1,71 @@
+package room_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/meet-clone/backend/internal/adapters/output/memory"
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+func TestJoinRoomConcurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRoomRepository()
+	svc := room.NewService(repo, room.DefaultPolicy())
+
+	rm, err := svc.CreateRoom(ctx, "host", room.CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+
+	// More users than the room can hold try to join at the same time
+	const users = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, users)
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			userID := fmt.Sprintf("user-%d", i)
+			for {
+				_, err := svc.JoinRoom(ctx, rm.ID, userID, userID, "")
+				// Like a client seeing 409, retry a join that kept losing
+				// the race
+				if appErr, ok := err.(*errors.AppError); ok && appErr.Type == errors.ErrorTypeConflict {
+					continue
+				}
+				errs <- err
+				return
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	joined := 0
+	for err := range errs {
+		if err == nil {
+			joined++
+			continue
+		}
+		appErr, ok := err.(*errors.AppError)
+		if !ok || appErr.Type != errors.ErrorTypeValidation || appErr.Message != "room is at maximum capacity" {
+			t.Errorf("join failed with %v, want the capacity error", err)
+		}
+	}
+
+	stored, err := repo.FindByID(ctx, rm.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+
+	if joined != stored.MaxCapacity {
+		t.Fatalf("%d joins succeeded, want the capacity of %d", joined, stored.MaxCapacity)
+	}
+	if active := len(stored.GetActiveParticipants()); active != joined {
+		t.Fatalf("%d joins succeeded but room holds %d participants", joined, active)
+	}
+}
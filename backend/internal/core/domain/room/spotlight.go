@@ -0,0 +1,40 @@
+package room
+
+import "strconv"
+
+// AddSpotlight puts an active participant in the spotlight, which every
+// client's layout focuses on. At most limit participants fit.
+func (r *Room) AddSpotlight(userID string, limit int) error {
+	if _, ok := r.activeParticipant(userID); !ok {
+		return &RoomError{Message: "participant not found in room"}
+	}
+
+	for _, id := range r.Spotlight {
+		if id == userID {
+			return &RoomError{Message: "participant is already spotlighted"}
+		}
+	}
+
+	if len(r.Spotlight) >= limit {
+		return &RoomError{Message: "at most " + strconv.Itoa(limit) + " participants can be spotlighted"}
+	}
+
+	r.Spotlight = append(r.Spotlight, userID)
+	return nil
+}
+
+// RemoveSpotlight takes the user out of the spotlight. It reports whether
+// the user was spotlighted.
+func (r *Room) RemoveSpotlight(userID string) bool {
+	for i, id := range r.Spotlight {
+		if id == userID {
+			r.Spotlight = append(r.Spotlight[:i], r.Spotlight[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) ClearSpotlight() {
+	r.Spotlight = []string{}
+}
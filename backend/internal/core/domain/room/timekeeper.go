@@ -0,0 +1,144 @@
+package room
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/meet-clone/backend/internal/pkg/logger"
+)
+
+type TimekeeperConfig struct {
+	// Interval is how often time-limited rooms are checked.
+	Interval time.Duration
+	// Warnings are the remaining times at which participants are warned
+	// that the meeting is about to end, for example 10m and 1m.
+	Warnings []time.Duration
+}
+
+// Timekeeper warns participants before a time-limited meeting ends and
+// ends it when the time is up.
+type Timekeeper struct {
+	finisher
+	repo     Repository
+	service  Service
+	interval time.Duration
+	warnings []time.Duration
+
+	mu sync.Mutex
+	// sent holds the warnings already delivered per room. It is reset when
+	// the room's expiry changes, so an extended meeting is warned again.
+	sent map[string]*sentWarnings
+}
+
+type sentWarnings struct {
+	expiresAt time.Time
+	smallest  time.Duration
+}
+
+func NewTimekeeper(repo Repository, service Service, presence Presence, events EventPublisher, sessions SessionTerminator, config TimekeeperConfig) *Timekeeper {
+	warnings := append([]time.Duration(nil), config.Warnings...)
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i] > warnings[j]
+	})
+
+	return &Timekeeper{
+		finisher: finisher{
+			presence: presence,
+			events:   events,
+			sessions: sessions,
+		},
+		repo:     repo,
+		service:  service,
+		interval: config.Interval,
+		warnings: warnings,
+		sent:     make(map[string]*sentWarnings),
+	}
+}
+
+// Run checks time-limited rooms every Interval until ctx is cancelled.
+func (t *Timekeeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Check(ctx)
+		}
+	}
+}
+
+// Check warns about or ends every time-limited room once.
+func (t *Timekeeper) Check(ctx context.Context) {
+	rooms, err := listActiveRooms(ctx, t.repo)
+	if err != nil {
+		logger.Error.Printf("Timekeeper failed to list active rooms: %v", err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	active := make(map[string]bool, len(rooms))
+	for _, room := range rooms {
+		if !room.HasTimeLimit() {
+			continue
+		}
+		active[room.ID] = true
+		t.checkRoom(ctx, room, now)
+	}
+
+	for roomID := range t.sent {
+		if !active[roomID] {
+			delete(t.sent, roomID)
+		}
+	}
+}
+
+func (t *Timekeeper) checkRoom(ctx context.Context, room *Room, now time.Time) {
+	remaining := room.ExpiresAt.Sub(now)
+
+	if remaining <= 0 {
+		ended, err := t.service.CloseExpiredRoom(ctx, room.ID, now)
+		if err != nil {
+			logger.Error.Printf("Timekeeper failed to end room %s: %v", room.ID, err)
+			return
+		}
+		// A host extended the meeting after it was listed
+		if ended.IsActive() {
+			return
+		}
+		logger.Info.Printf("Timekeeper ended room %s after reaching its time limit", room.ID)
+		t.finish(ended, "time_limit")
+		return
+	}
+
+	sent, ok := t.sent[room.ID]
+	if !ok || !sent.expiresAt.Equal(room.ExpiresAt) {
+		sent = &sentWarnings{expiresAt: room.ExpiresAt}
+		t.sent[room.ID] = sent
+	}
+
+	// Only the closest threshold is announced, so a meeting that starts
+	// with less than ten minutes left does not get the ten-minute warning.
+	var due time.Duration
+	for _, w := range t.warnings {
+		if remaining <= w && (sent.smallest == 0 || w < sent.smallest) {
+			due = w
+		}
+	}
+	if due == 0 {
+		return
+	}
+
+	sent.smallest = due
+	t.events.Publish(room.ID, "meeting_ending_soon", map[string]interface{}{
+		"expires_at":        room.ExpiresAt,
+		"remaining_seconds": int64(remaining / time.Second),
+	})
+}
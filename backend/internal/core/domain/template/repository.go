@@ -0,0 +1,13 @@
+package template
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, template *Template) error
+	FindByID(ctx context.Context, id string) (*Template, error)
+	// FindVisible returns the templates owned by the user or shared with
+	// the organization.
+	FindVisible(ctx context.Context, ownerID, organizationID string) ([]*Template, error)
+	Update(ctx context.Context, template *Template) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,199 @@
+package template
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/meet-clone/backend/internal/core/domain/room"
+	"github.com/meet-clone/backend/internal/core/domain/user"
+	"github.com/meet-clone/backend/internal/pkg/errors"
+)
+
+// Input holds the editable fields of a template.
+type Input struct {
+	Name               string
+	Description        string
+	Title              string
+	MaxCapacity        int
+	MaxDurationMinutes int
+	Settings           room.Settings
+	// Shared makes the template available to the owner's organization.
+	Shared bool
+}
+
+// Users looks up the organization of template owners and viewers.
+type Users interface {
+	GetByID(ctx context.Context, id string) (*user.User, error)
+}
+
+type Service interface {
+	CreateTemplate(ctx context.Context, userID string, input Input) (*Template, error)
+	GetTemplate(ctx context.Context, id, userID string) (*Template, error)
+	ListTemplates(ctx context.Context, userID string) ([]*Template, error)
+	UpdateTemplate(ctx context.Context, id, userID string, input Input) (*Template, error)
+	DeleteTemplate(ctx context.Context, id, userID string) error
+	// RoomOptions returns the room options of a template the user can see.
+	RoomOptions(ctx context.Context, id, userID string) (room.CreateOptions, error)
+}
+
+type service struct {
+	repo  Repository
+	users Users
+}
+
+func NewService(repo Repository, users Users) Service {
+	return &service{
+		repo:  repo,
+		users: users,
+	}
+}
+
+func (s *service) CreateTemplate(ctx context.Context, userID string, input Input) (*Template, error) {
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	t := NewTemplate(userID, strings.TrimSpace(input.Name))
+	if err := s.apply(ctx, t, userID, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, t); err != nil {
+		return nil, errors.NewInternalError("failed to create template", err)
+	}
+
+	return t, nil
+}
+
+func (s *service) GetTemplate(ctx context.Context, id, userID string) (*Template, error) {
+	t, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("template not found")
+	}
+
+	if t.OwnerID == userID {
+		return t, nil
+	}
+
+	if t.OrganizationID != "" {
+		u, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if u.OrganizationID == t.OrganizationID {
+			return t, nil
+		}
+	}
+
+	// Templates of other users are not revealed
+	return nil, errors.NewNotFoundError("template not found")
+}
+
+func (s *service) ListTemplates(ctx context.Context, userID string) ([]*Template, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := s.repo.FindVisible(ctx, userID, u.OrganizationID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to list templates", err)
+	}
+
+	return templates, nil
+}
+
+func (s *service) UpdateTemplate(ctx context.Context, id, userID string, input Input) (*Template, error) {
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	t, err := s.GetTemplate(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.OwnerID != userID {
+		return nil, errors.NewForbiddenError("only the template owner can change it")
+	}
+
+	t.Name = strings.TrimSpace(input.Name)
+	if err := s.apply(ctx, t, userID, input); err != nil {
+		return nil, err
+	}
+	t.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, t); err != nil {
+		return nil, errors.NewInternalError("failed to update template", err)
+	}
+
+	return t, nil
+}
+
+func (s *service) DeleteTemplate(ctx context.Context, id, userID string) error {
+	t, err := s.GetTemplate(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if t.OwnerID != userID {
+		return errors.NewForbiddenError("only the template owner can delete it")
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return errors.NewInternalError("failed to delete template", err)
+	}
+
+	return nil
+}
+
+func (s *service) RoomOptions(ctx context.Context, id, userID string) (room.CreateOptions, error) {
+	t, err := s.GetTemplate(ctx, id, userID)
+	if err != nil {
+		return room.CreateOptions{}, err
+	}
+
+	return t.RoomOptions(), nil
+}
+
+// apply copies the input onto the template.
+func (s *service) apply(ctx context.Context, t *Template, userID string, input Input) error {
+	t.Description = input.Description
+	t.Title = input.Title
+	t.MaxCapacity = input.MaxCapacity
+	t.MaxDurationMinutes = input.MaxDurationMinutes
+	t.Settings = input.Settings
+	t.OrganizationID = ""
+
+	if !input.Shared {
+		return nil
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.OrganizationID == "" {
+		return errors.NewValidationError("only members of an organization can share templates")
+	}
+	t.OrganizationID = u.OrganizationID
+
+	return nil
+}
+
+func validate(input Input) error {
+	if strings.TrimSpace(input.Name) == "" {
+		return errors.NewValidationError("name is required")
+	}
+	if input.MaxCapacity < 0 {
+		return errors.NewValidationError("max capacity cannot be negative")
+	}
+	if input.MaxDurationMinutes < 0 {
+		return errors.NewValidationError("max duration cannot be negative")
+	}
+	if err := input.Settings.Validate(); err != nil {
+		return errors.NewValidationError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package template
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-clone/backend/internal/core/domain/room"
+)
+
+// Template is a saved room configuration for meetings that recur, such as
+// an all-hands or a standup.
+type Template struct {
+	ID          string `json:"id" bson:"_id"`
+	Name        string `json:"name" bson:"name"`
+	Description string `json:"description" bson:"description"`
+	OwnerID     string `json:"owner_id" bson:"owner_id"`
+	// OrganizationID shares the template with every member of the
+	// organization. Only the owner may change it.
+	OrganizationID     string        `json:"organization_id,omitempty" bson:"organization_id,omitempty"`
+	Title              string        `json:"title" bson:"title"`
+	MaxCapacity        int           `json:"max_capacity" bson:"max_capacity"`
+	MaxDurationMinutes int           `json:"max_duration_minutes" bson:"max_duration_minutes"`
+	Settings           room.Settings `json:"settings" bson:"settings"`
+	CreatedAt          time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+func NewTemplate(ownerID, name string) *Template {
+	now := time.Now()
+	return &Template{
+		ID:        uuid.New().String(),
+		Name:      name,
+		OwnerID:   ownerID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// RoomOptions returns the options for creating a room from the template.
+func (t *Template) RoomOptions() room.CreateOptions {
+	return room.CreateOptions{
+		Title:       t.Title,
+		MaxCapacity: t.MaxCapacity,
+		MaxDuration: time.Duration(t.MaxDurationMinutes) * time.Minute,
+		Settings:    t.Settings,
+	}
+}
@@ -2,6 +2,8 @@ package user
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/meet-clone/backend/internal/pkg/errors"
 )
@@ -14,11 +16,17 @@ type Service interface {
 
 type service struct {
 	repo Repository
+	// organizations maps email domains to the organization their users
+	// belong to.
+	organizations map[string]string
 }
 
-func NewService(repo Repository) Service {
+// NewService returns the user service. Users whose email domain is in
+// organizations join that organization when they register or log in.
+func NewService(repo Repository, organizations map[string]string) Service {
 	return &service{
-		repo: repo,
+		repo:          repo,
+		organizations: organizations,
 	}
 }
 
@@ -34,6 +42,7 @@ func (s *service) Register(ctx context.Context, email, password, name string) (*
 	if err != nil {
 		return nil, errors.NewInternalError("failed to create user", err)
 	}
+	user.OrganizationID, _ = s.organization(email)
 
 	// Save to database
 	if err := s.repo.Create(ctx, user); err != nil {
@@ -53,9 +62,28 @@ func (s *service) Login(ctx context.Context, email, password string) (*User, err
 		return nil, errors.NewUnauthorizedError("invalid email or password")
 	}
 
+	// Users registered before their domain was mapped join on login
+	if organizationID, ok := s.organization(user.Email); ok && organizationID != user.OrganizationID {
+		user.OrganizationID = organizationID
+		user.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, user); err != nil {
+			return nil, errors.NewInternalError("failed to update user", err)
+		}
+	}
+
 	return user, nil
 }
 
+// organization returns the organization of the email's domain, if any.
+func (s *service) organization(email string) (string, bool) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return "", false
+	}
+	organizationID, ok := s.organizations[strings.ToLower(email[at+1:])]
+	return organizationID, ok
+}
+
 func (s *service) GetByID(ctx context.Context, id string) (*User, error) {
 	user, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -13,6 +13,9 @@ type User struct {
 	Avatar    string    `json:"avatar" bson:"avatar"`
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+
+	// OrganizationID is the organization the user belongs to, if any.
+	OrganizationID string `json:"organization_id,omitempty" bson:"organization_id,omitempty"`
 }
 
 func NewUser(email, password, name string) (*User, error) {
@@ -12,6 +12,7 @@ const (
 	ErrorTypeAlreadyExists ErrorType = "ALREADY_EXISTS"
 	ErrorTypeInternal      ErrorType = "INTERNAL_ERROR"
 	ErrorTypeForbidden     ErrorType = "FORBIDDEN"
+	ErrorTypeConflict      ErrorType = "CONFLICT"
 )
 
 type AppError struct {
@@ -69,3 +70,12 @@ func NewForbiddenError(message string) *AppError {
 		Message: message,
 	}
 }
+
+// NewConflictError reports a request that lost a race against a concurrent
+// change and can be retried.
+func NewConflictError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeConflict,
+		Message: message,
+	}
+}